@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// academicCalendar describes how an institution divides its academic year
+// into terms. Seasons give every term a code suffix, a name and a yearly
+// start date; Terms override the dates of individual semesters whose start
+// or end moved.
+type academicCalendar struct {
+	Seasons []season    `yaml:"seasons" toml:"seasons"`
+	Terms   []termDates `yaml:"terms" toml:"terms"`
+}
+
+// season is a recurring term of the academic year. Seasons are listed in
+// the order they occur, beginning with the one that opens the academic year.
+type season struct {
+	Code string `yaml:"code" toml:"code"`
+	Name string `yaml:"name" toml:"name"`
+	// Start is the month and day the season usually begins, as MM-DD.
+	Start string `yaml:"start" toml:"start"`
+	// YearOffset is added to the academic year to give the year a term of
+	// this season is reported under.
+	YearOffset int `yaml:"year_offset" toml:"year_offset"`
+
+	start monthDay
+}
+
+// termDates pins the first and last day of one semester, as YYYY-MM-DD.
+type termDates struct {
+	Code  string `yaml:"code" toml:"code"`
+	Start string `yaml:"start" toml:"start"`
+	End   string `yaml:"end" toml:"end"`
+
+	start, end time.Time
+}
+
+type monthDay struct {
+	month time.Month
+	day   int
+}
+
+func (d monthDay) before(other monthDay) bool {
+	return d.month < other.month || d.month == other.month && d.day < other.day
+}
+
+// defaultCalendar returns the HKUST calendar, whose terms begin on the first
+// of September, January, February and June.
+func defaultCalendar() *academicCalendar {
+	cal := &academicCalendar{
+		Seasons: []season{
+			{Code: "10", Name: "Fall", Start: "09-01"},
+			{Code: "20", Name: "Winter", Start: "01-01"},
+			{Code: "30", Name: "Spring", Start: "02-01", YearOffset: 1},
+			{Code: "40", Name: "Summer", Start: "06-01", YearOffset: 1},
+		},
+	}
+	// The built-in calendar is valid, so validation only parses its dates.
+	cal.validate()
+	return cal
+}
+
+// loadCalendar reads an academic calendar from a YAML or TOML file.
+func loadCalendar(name string) (*academicCalendar, error) {
+	var cal academicCalendar
+	if err := decodeConfigFile(name, &cal); err != nil {
+		return nil, err
+	}
+	if err := cal.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &cal, nil
+}
+
+// validate checks the calendar for consistency and parses its dates.
+func (cal *academicCalendar) validate() error {
+	if len(cal.Seasons) == 0 {
+		return fmt.Errorf("%w: no seasons defined", ErrInvalidCalendar)
+	}
+	wrapped := false
+	for i := range cal.Seasons {
+		s := &cal.Seasons[i]
+		if len(s.Code) != 2 || s.Code[0] < '0' || s.Code[0] > '9' || s.Code[1] < '0' || s.Code[1] > '9' {
+			return fmt.Errorf("%w: season code %q must be two digits", ErrInvalidCalendar, s.Code)
+		}
+		if cal.seasonIndex(s.Code) != i {
+			return fmt.Errorf("%w: season code %q is defined twice", ErrInvalidCalendar, s.Code)
+		}
+		if s.Name == "" {
+			return fmt.Errorf("%w: season %s has no name", ErrInvalidCalendar, s.Code)
+		}
+		t, err := time.Parse("01-02", s.Start)
+		if err != nil {
+			return fmt.Errorf("%w: season %s start %q must be MM-DD", ErrInvalidCalendar, s.Code, s.Start)
+		}
+		s.start = monthDay{month: t.Month(), day: t.Day()}
+		if i == 0 {
+			continue
+		}
+		// Seasons run in order through the academic year, which may cross
+		// into the next calendar year once.
+		if prev := cal.Seasons[i-1].start; !prev.before(s.start) {
+			if wrapped || !s.start.before(cal.Seasons[0].start) {
+				return fmt.Errorf("%w: season %s does not start after season %s", ErrInvalidCalendar, s.Code, cal.Seasons[i-1].Code)
+			}
+			wrapped = true
+		} else if wrapped && !s.start.before(cal.Seasons[0].start) {
+			return fmt.Errorf("%w: season %s starts after the academic year ends", ErrInvalidCalendar, s.Code)
+		}
+	}
+
+	for i := range cal.Terms {
+		d := &cal.Terms[i]
+		if _, err := cal.parseTerm(d.Code); err != nil {
+			return fmt.Errorf("%w: term %q: %w", ErrInvalidCalendar, d.Code, err)
+		}
+		if slices.IndexFunc(cal.Terms, func(other termDates) bool { return other.Code == d.Code }) != i {
+			return fmt.Errorf("%w: term %s is defined twice", ErrInvalidCalendar, d.Code)
+		}
+		var err error
+		if d.start, err = time.Parse(dateLayout, d.Start); err != nil {
+			return fmt.Errorf("%w: term %s start %q must be YYYY-MM-DD", ErrInvalidCalendar, d.Code, d.Start)
+		}
+		if d.end, err = time.Parse(dateLayout, d.End); err != nil {
+			return fmt.Errorf("%w: term %s end %q must be YYYY-MM-DD", ErrInvalidCalendar, d.Code, d.End)
+		}
+		if d.end.Before(d.start) {
+			return fmt.Errorf("%w: term %s ends before it starts", ErrInvalidCalendar, d.Code)
+		}
+	}
+	return nil
+}
+
+func (cal *academicCalendar) seasonIndex(code string) int {
+	return slices.IndexFunc(cal.Seasons, func(s season) bool { return s.Code == code })
+}
+
+func (cal *academicCalendar) explicitTerm(code string) (termDates, bool) {
+	i := slices.IndexFunc(cal.Terms, func(d termDates) bool { return d.Code == code })
+	if i < 0 {
+		return termDates{}, false
+	}
+	return cal.Terms[i], true
+}
+
+// onDate returns midnight in loc on the calendar date of d.
+func onDate(d time.Time, loc *time.Location) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDefaultCalendar_SemesterDates(t *testing.T) {
+	tests := []struct {
+		code, start, end string
+	}{
+		{"2510", "2025-09-01", "2025-12-31"},
+		{"2520", "2026-01-01", "2026-01-31"},
+		{"2530", "2026-02-01", "2026-05-31"},
+		{"2540", "2026-06-01", "2026-08-31"},
+	}
+	for _, tt := range tests {
+		s, err := defaultCalendar().parseSemester(tt.code)
+		if err != nil {
+			t.Fatalf("parseSemester(%q) error: %v", tt.code, err)
+		}
+		if s.Start != tt.start || s.End != tt.end {
+			t.Errorf("parseSemester(%q) dates = %s to %s, want %s to %s", tt.code, s.Start, s.End, tt.start, tt.end)
+		}
+	}
+}
+
+func TestLoadCalendar(t *testing.T) {
+	p := writeConfigFile(t, "calendar.yaml", `
+seasons:
+  - {code: "10", name: Autumn, start: "08-25"}
+  - {code: "30", name: Spring, start: "01-15", year_offset: 1}
+terms:
+  - {code: "2530", start: "2026-01-20", end: "2026-05-15"}
+`)
+	cal, err := loadCalendar(p)
+	if err != nil {
+		t.Fatalf("loadCalendar() error: %v", err)
+	}
+
+	s, err := cal.parseSemester("2530")
+	if err != nil {
+		t.Fatalf("parseSemester() error: %v", err)
+	}
+	if s.Name != "2025 - 2026 Spring" || s.Year != "2026" || s.Start != "2026-01-20" || s.End != "2026-05-15" {
+		t.Errorf("parseSemester(2530) = %+v", s)
+	}
+	if _, err := cal.parseSemester("2520"); !errors.Is(err, ErrInvalidSemesterCode) {
+		t.Errorf("parseSemester(2520) error = %v, want ErrInvalidSemesterCode", err)
+	}
+
+	codes := []struct {
+		time time.Time
+		want string
+	}{
+		{time.Date(2025, 8, 25, 0, 0, 0, 0, time.UTC), "2510"},
+		{time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC), "2510"},
+		{time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC), "2530"},
+		{time.Date(2026, 8, 24, 0, 0, 0, 0, time.UTC), "2530"},
+		{time.Date(2026, 8, 25, 0, 0, 0, 0, time.UTC), "2610"},
+	}
+	for _, tt := range codes {
+		if got, _ := cal.semesterCodeForTime(tt.time); got != tt.want {
+			t.Errorf("semesterCodeForTime(%v) = %q, want %q", tt.time, got, tt.want)
+		}
+	}
+}
+
+func TestLoadCalendar_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no seasons":       "terms: []\n",
+		"bad season code":  "seasons: [{code: A1, name: Fall, start: \"09-01\"}]\n",
+		"duplicate season": "seasons: [{code: \"10\", name: Fall, start: \"09-01\"}, {code: \"10\", name: Spring, start: \"02-01\"}]\n",
+		"unnamed season":   "seasons: [{code: \"10\", start: \"09-01\"}]\n",
+		"bad season start": "seasons: [{code: \"10\", name: Fall, start: \"September\"}]\n",
+		"out of order":     "seasons: [{code: \"10\", name: Fall, start: \"09-01\"}, {code: \"20\", name: Summer, start: \"06-01\"}, {code: \"30\", name: Spring, start: \"02-01\"}]\n",
+		"unknown term":     "seasons: [{code: \"10\", name: Fall, start: \"09-01\"}]\nterms: [{code: \"2530\", start: \"2026-02-01\", end: \"2026-05-31\"}]\n",
+		"term ends early":  "seasons: [{code: \"10\", name: Fall, start: \"09-01\"}]\nterms: [{code: \"2510\", start: \"2025-09-01\", end: \"2025-08-31\"}]\n",
+		"bad term date":    "seasons: [{code: \"10\", name: Fall, start: \"09-01\"}]\nterms: [{code: \"2510\", start: \"1 Sep 2025\", end: \"2025-12-31\"}]\n",
+		"unknown field":    "seasons: [{code: \"10\", name: Fall, start: \"09-01\", begins: \"09-01\"}]\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := loadCalendar(writeConfigFile(t, "calendar.yaml", content)); err == nil {
+				t.Error("loadCalendar() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLoadConfig_CalendarFile(t *testing.T) {
+	p := writeConfigFile(t, "calendar.toml", `
+[[seasons]]
+code = "10"
+name = "Fall"
+start = "09-01"
+`)
+	cfg := mustLoadConfig(t, "-calendar-file", p)
+	if cfg.Calendar == nil || len(cfg.Calendar.Seasons) != 1 {
+		t.Fatalf("Calendar = %+v, want one season", cfg.Calendar)
+	}
+	if _, err := loadConfig([]string{"-calendar-file", writeConfigFile(t, "calendar.toml", "seasons = []\n")}); !errors.Is(err, ErrInvalidCalendar) {
+		t.Errorf("loadConfig() with empty calendar error = %v, want ErrInvalidCalendar", err)
+	}
+}
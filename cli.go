@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+)
+
+// command is a subcommand that uses the scraper directly, without starting
+// the servers.
+type command struct {
+	name  string
+	args  string
+	usage string
+	run   func(ctx context.Context, a *app, args []string, stdout io.Writer) error
+}
+
+var commands = []command{
+	{"get", "CODE...", "print courses as JSON", runGet},
+	{"dept", "DEPARTMENT", "print the courses of a department as JSON", runDept},
+	{"semester", "[CODE|current|next]", "print a semester, by default the current one", runSemester},
+	{"scrape", "[-dept CODES] [-out FILE]", "scrape departments, by default all of them, into a JSON file", runScrape},
+	{"export", "-out DIR|FILE.tar.gz", "export the catalogue of a semester as a dataset for the dataset setting", runExport},
+	{"requirements", "-program FILE [CODE...]", "check completed courses against a requirement definition file", runRequirements},
+}
+
+func findCommand(name string) (command, bool) {
+	i := slices.IndexFunc(commands, func(c command) bool { return c.name == name })
+	if i < 0 {
+		return command{}, false
+	}
+	return commands[i], true
+}
+
+// printCommands lists the subcommands in flag usage output.
+func printCommands(w io.Writer) {
+	fmt.Fprintln(w, "\nCommands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %s %s\n    \t%s\n", c.name, c.args, c.usage)
+	}
+}
+
+// runCommand runs the subcommand named by args[0] against a scraper for the
+// configured upstream.
+func runCommand(ctx context.Context, cfg config, args []string, stdout, stderr io.Writer) error {
+	cmd, ok := findCommand(args[0])
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+	cal := cfg.Calendar
+	if cal == nil {
+		cal = defaultCalendar()
+	}
+	a := &app{
+		config:          cfg,
+		cache:           make(map[string]*Course),
+		departmentCache: []department{},
+		// Progress is only worth reporting when something goes wrong; stdout
+		// carries the command's output.
+		logger:   slog.New(slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: slog.LevelWarn})),
+		calendar: cal,
+	}
+	current, err := cal.currentSemesterCode()
+	if err != nil {
+		return err
+	}
+	a.endpoint = fmt.Sprintf("%s/%s", cfg.BaseURL, current)
+	return cmd.run(ctx, a, args[1:], stdout)
+}
+
+// commandFlags returns a flag set for a subcommand, with the -semester flag
+// every command scraping courses accepts.
+func commandFlags(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	semester := fs.String("semester", "current", "semester code, current or next")
+	return fs, semester
+}
+
+// useSemester points the scraper at the given semester.
+func (a *app) useSemester(code string) error {
+	code, err := a.resolveSemesterCode(code)
+	if err != nil {
+		return err
+	}
+	if _, err := a.calendar.parseTerm(code); err != nil {
+		return fmt.Errorf("semester %q: %w", code, err)
+	}
+	a.endpoint = fmt.Sprintf("%s/%s", a.config.BaseURL, code)
+	return nil
+}
+
+// scrapeDepartments scrapes each department once and returns its courses.
+func (a *app) scrapeDepartments(ctx context.Context, departments []string) ([]*Course, error) {
+	collector := a.newCollector(ctx, a.remember)
+	endpoint := a.getEndpoint()
+	for _, d := range departments {
+		if err := a.visitDepartment(collector, endpoint, d); err != nil {
+			return nil, fmt.Errorf("scraping %s: %w", d, err)
+		}
+	}
+	var courses []*Course
+	for _, d := range departments {
+		courses = append(courses, a.cachedCourses(d)...)
+	}
+	return courses, nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func runGet(ctx context.Context, a *app, args []string, stdout io.Writer) error {
+	fs, semester := commandFlags("get")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("get: no course codes given")
+	}
+	if err := a.useSemester(*semester); err != nil {
+		return err
+	}
+	var codes, departments []string
+	for _, raw := range fs.Args() {
+		code, department, err := normalizeCourseCode(raw)
+		if err != nil {
+			return fmt.Errorf("%q: %w", raw, err)
+		}
+		codes = append(codes, code)
+		if !slices.Contains(departments, department) {
+			departments = append(departments, department)
+		}
+	}
+	if _, err := a.scrapeDepartments(ctx, departments); err != nil {
+		return err
+	}
+	var courses []*Course
+	a.mu.RLock()
+	for _, code := range codes {
+		course, ok := a.cachedCourseLocked(code)
+		if !ok {
+			a.mu.RUnlock()
+			return fmt.Errorf("course %s not found", code)
+		}
+		courses = append(courses, course)
+	}
+	a.mu.RUnlock()
+	if len(courses) == 1 {
+		return writeJSON(stdout, courses[0])
+	}
+	return writeJSON(stdout, courses)
+}
+
+func runDept(ctx context.Context, a *app, args []string, stdout io.Writer) error {
+	fs, semester := commandFlags("dept")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("dept: exactly one department code must be given")
+	}
+	if err := a.useSemester(*semester); err != nil {
+		return err
+	}
+	department := strings.ToUpper(fs.Arg(0))
+	courses, err := a.scrapeDepartments(ctx, []string{department})
+	if err != nil {
+		return err
+	}
+	if len(courses) == 0 {
+		return fmt.Errorf("department %s has no courses", department)
+	}
+	return writeJSON(stdout, courses)
+}
+
+func runSemester(_ context.Context, a *app, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("semester", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	code := "current"
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		code = fs.Arg(0)
+	default:
+		return errors.New("semester: at most one semester code may be given")
+	}
+	code, err := a.resolveSemesterCode(code)
+	if err != nil {
+		return err
+	}
+	s, err := a.calendar.parseSemester(code)
+	if err != nil {
+		return fmt.Errorf("semester %q: %w", code, err)
+	}
+	return writeJSON(stdout, s)
+}
+
+func runScrape(ctx context.Context, a *app, args []string, stdout io.Writer) error {
+	fs, semester := commandFlags("scrape")
+	dept := fs.String("dept", "", "comma-separated department codes; all departments if empty")
+	out := fs.String("out", "", "output file; standard output if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("scrape: unexpected argument %q", fs.Arg(0))
+	}
+	if err := a.useSemester(*semester); err != nil {
+		return err
+	}
+	var departments []string
+	for d := range strings.SplitSeq(*dept, ",") {
+		if d = strings.ToUpper(strings.TrimSpace(d)); d != "" && !slices.Contains(departments, d) {
+			departments = append(departments, d)
+		}
+	}
+	if len(departments) == 0 {
+		discovered, err := a.discoverDepartments(ctx)
+		if err != nil {
+			return err
+		}
+		for _, d := range discovered {
+			departments = append(departments, d.Code)
+		}
+	}
+	courses, err := a.scrapeDepartments(ctx, departments)
+	if err != nil {
+		return err
+	}
+	if courses == nil {
+		courses = []*Course{}
+	}
+	if *out == "" {
+		return writeJSON(stdout, courses)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := writeJSON(f, courses); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func runRequirements(ctx context.Context, a *app, args []string, stdout io.Writer) error {
+	fs, semester := commandFlags("requirements")
+	name := fs.String("program", "", "requirement definition file in YAML, TOML or JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return errors.New("requirements: -program must be given")
+	}
+	p, err := loadProgram(*name)
+	if err != nil {
+		return err
+	}
+	if err := a.useSemester(*semester); err != nil {
+		return err
+	}
+	// Scrape the departments of the completed courses, for their exclusions,
+	// and those the requirements draw on, for the courses left to take.
+	var completed, departments []string
+	for _, raw := range fs.Args() {
+		code, department, err := normalizeCourseCode(raw)
+		if err != nil {
+			return fmt.Errorf("%q: %w", raw, err)
+		}
+		if !slices.Contains(completed, code) {
+			completed = append(completed, code)
+		}
+		if !slices.Contains(departments, department) {
+			departments = append(departments, department)
+		}
+	}
+	for _, r := range p.Requirements {
+		for _, pattern := range r.patterns {
+			if !slices.Contains(departments, pattern.code.Department) {
+				departments = append(departments, pattern.code.Department)
+			}
+		}
+	}
+	if _, err := a.scrapeDepartments(ctx, departments); err != nil {
+		return err
+	}
+	return writeJSON(stdout, a.checkRequirements(p, completed))
+}
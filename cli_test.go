@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// runTestCommand runs a subcommand against an upstream serving the COMP
+// department only.
+func runTestCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cfg := defaultConfig()
+	cfg.BaseURL = upstreamServer(t, "COMP").URL
+	var stdout, stderr bytes.Buffer
+	err := runCommand(context.Background(), cfg, args, &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestLoadConfig_Command(t *testing.T) {
+	cfg := mustLoadConfig(t, "-base-url", "http://example.com", "scrape", "-dept", "COMP")
+	if want := []string{"scrape", "-dept", "COMP"}; !slices.Equal(cfg.Command, want) {
+		t.Errorf("Command = %q, want %q", cfg.Command, want)
+	}
+	if cfg.BaseURL != "http://example.com" {
+		t.Errorf("BaseURL = %q, want %q", cfg.BaseURL, "http://example.com")
+	}
+	if _, err := loadConfig([]string{"serve"}); err == nil {
+		t.Error("loadConfig(serve) succeeded, want unexpected argument error")
+	}
+}
+
+func TestRunCommand_Get(t *testing.T) {
+	out, err := runTestCommand(t, "get", "comp 1021")
+	if err != nil {
+		t.Fatalf("get error: %v", err)
+	}
+	var course Course
+	if err := json.Unmarshal([]byte(out), &course); err != nil {
+		t.Fatalf("get printed %q: %v", out, err)
+	}
+	if course.Code != "COMP1021" || course.Credits != 3 {
+		t.Errorf("get printed %+v", course)
+	}
+
+	if _, err := runTestCommand(t, "get", "COMP9999"); err == nil {
+		t.Error("get COMP9999 succeeded, want not found error")
+	}
+}
+
+func TestRunCommand_Dept(t *testing.T) {
+	out, err := runTestCommand(t, "dept", "comp")
+	if err != nil {
+		t.Fatalf("dept error: %v", err)
+	}
+	var courses []Course
+	if err := json.Unmarshal([]byte(out), &courses); err != nil {
+		t.Fatalf("dept printed %q: %v", out, err)
+	}
+	if len(courses) != 1 || courses[0].Code != "COMP1021" {
+		t.Errorf("dept printed %+v", courses)
+	}
+}
+
+func TestRunCommand_Semester(t *testing.T) {
+	out, err := runTestCommand(t, "semester", "2510")
+	if err != nil {
+		t.Fatalf("semester error: %v", err)
+	}
+	var s semester
+	if err := json.Unmarshal([]byte(out), &s); err != nil {
+		t.Fatalf("semester printed %q: %v", out, err)
+	}
+	if s.Code != "2510" || s.Name != "2025 - 2026 Fall" {
+		t.Errorf("semester printed %+v", s)
+	}
+
+	if _, err := runTestCommand(t, "semester", "2599"); err == nil {
+		t.Error("semester 2599 succeeded, want error")
+	}
+}
+
+func TestRunCommand_Requirements(t *testing.T) {
+	program := writeConfigFile(t, "program.yaml", `
+name: Minor in Computer Science
+requirements:
+  - name: Introductory programming
+    from: [COMP1021, COMP1022P]
+`)
+	out, err := runTestCommand(t, "requirements", "-semester", "2510", "-program", program, "comp 1021")
+	if err != nil {
+		t.Fatalf("requirements error: %v", err)
+	}
+	var got requirementsCheck
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("requirements printed %q: %v", out, err)
+	}
+	if !got.Met || !slices.Equal(got.Requirements[0].SatisfiedBy, []string{"COMP1021"}) {
+		t.Errorf("requirements printed %+v", got)
+	}
+	if _, err := runTestCommand(t, "requirements", "comp 1021"); err == nil {
+		t.Error("requirements without -program succeeded, want error")
+	}
+}
+
+func TestRunCommand_Scrape(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "courses.json")
+	// Without -dept every department on the index is scraped.
+	if _, err := runTestCommand(t, "scrape", "-semester", "2510", "--out", out); err != nil {
+		t.Fatalf("scrape error: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var courses []Course
+	if err := json.Unmarshal(data, &courses); err != nil {
+		t.Fatalf("scrape wrote %q: %v", data, err)
+	}
+	if len(courses) != 1 || courses[0].Code != "COMP1021" {
+		t.Errorf("scrape wrote %+v", courses)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// maxCompareCourses bounds the number of courses compared at once.
+const maxCompareCourses = 5
+
+// valueDiff compares a single value of several courses, by course code.
+type valueDiff[T comparable] struct {
+	Same   bool         `json:"same"`
+	Values map[string]T `json:"values"`
+}
+
+func newValueDiff[T comparable](courses []*Course, value func(*Course) T) valueDiff[T] {
+	d := valueDiff[T]{Same: true, Values: make(map[string]T, len(courses))}
+	for _, course := range courses {
+		v := value(course)
+		if len(d.Values) > 0 && v != d.Values[courses[0].Code] {
+			d.Same = false
+		}
+		d.Values[course.Code] = v
+	}
+	return d
+}
+
+// setDiff compares a set of values of several courses: those all courses
+// share, and those particular to each course.
+type setDiff struct {
+	Common []string            `json:"common"`
+	Only   map[string][]string `json:"only"`
+}
+
+func newSetDiff(courses []*Course, values func(*Course) []string) setDiff {
+	sets := make([][]string, len(courses))
+	for i, course := range courses {
+		sets[i] = slices.Compact(slices.Sorted(slices.Values(values(course))))
+	}
+	d := setDiff{Common: []string{}, Only: make(map[string][]string, len(courses))}
+	for _, v := range sets[0] {
+		if !slices.ContainsFunc(sets[1:], func(set []string) bool { return !slices.Contains(set, v) }) {
+			d.Common = append(d.Common, v)
+		}
+	}
+	for i, course := range courses {
+		only := []string{}
+		for _, v := range sets[i] {
+			if !slices.Contains(d.Common, v) {
+				only = append(only, v)
+			}
+		}
+		d.Only[course.Code] = only
+	}
+	return d
+}
+
+func sectionTimes(course *Course) []string {
+	var times []string
+	for _, s := range course.Schedule {
+		if s.Time != "" {
+			times = append(times, s.Time)
+		}
+	}
+	return times
+}
+
+func totalQuota(course *Course) int {
+	total := 0
+	for _, s := range course.Schedule {
+		total += s.Quota
+	}
+	return total
+}
+
+// compareCourses returns the differences between courses, which must hold
+// at least one course.
+func compareCourses(courses []*Course) courseComparison {
+	codes := make([]string, 0, len(courses))
+	for _, course := range courses {
+		codes = append(codes, course.Code)
+	}
+	return courseComparison{
+		Courses:       codes,
+		Titles:        newValueDiff(courses, func(c *Course) string { return c.Title }),
+		Credits:       newValueDiff(courses, func(c *Course) float64 { return c.Credits }),
+		Prerequisites: newValueDiff(courses, func(c *Course) string { return c.Prerequisites }),
+		Exclusions:    newSetDiff(courses, func(c *Course) []string { return c.Exclusions }),
+		SectionTimes:  newSetDiff(courses, sectionTimes),
+		Instructors: newSetDiff(courses, func(c *Course) []string {
+			return slices.Collect(maps.Keys(c.Instructors))
+		}),
+		Quotas: newValueDiff(courses, totalQuota),
+	}
+}
+
+func (a *app) HandleCompareCourses(c echo.Context) error {
+	a.logger.Info("GET /v1/compare", "courses", c.QueryParam("courses"))
+	var codes []string
+	for raw := range strings.SplitSeq(c.QueryParam("courses"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		code, _, err := normalizeCourseCode(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{
+				Status:  "error",
+				Message: fmt.Sprintf("%q: %s", raw, err),
+			})
+			return nil
+		}
+		codes = append(codes, code)
+	}
+	if len(codes) < 2 || len(codes) > maxCompareCourses {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("courses must list between 2 and %d course codes", maxCompareCourses),
+		})
+		return nil
+	}
+
+	resp := a.batchGetCourses(c.Request().Context(), codes)
+	if len(resp.NotFound) > 0 {
+		c.JSON(http.StatusNotFound, errorResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("courses not found: %s", strings.Join(resp.NotFound, ", ")),
+		})
+		return nil
+	}
+	// Cross-listed codes resolve to the same course.
+	var courses []*Course
+	for _, course := range resp.Courses {
+		if !slices.Contains(courses, course) {
+			courses = append(courses, course)
+		}
+	}
+	if len(courses) < 2 {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: "courses must name at least 2 different courses",
+		})
+		return nil
+	}
+	c.JSON(http.StatusOK, compareCourses(courses))
+	return nil
+}
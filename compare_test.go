@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func TestHandleCompareCourses(t *testing.T) {
+	a := fixtureApp()
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/compare?courses=comp1021,COMP-1022P", a)
+
+	if err := a.HandleCompareCourses(c); err != nil {
+		t.Fatalf("HandleCompareCourses() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var got courseComparison
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !slices.Equal(got.Courses, []string{"COMP1021", "COMP1022P"}) {
+		t.Errorf("courses = %v", got.Courses)
+	}
+	if !got.Credits.Same || got.Credits.Values["COMP1022P"] != 3 {
+		t.Errorf("credits = %+v, want same 3", got.Credits)
+	}
+	if got.Quotas.Same || got.Quotas.Values["COMP1021"] != 240 || got.Quotas.Values["COMP1022P"] != 150 {
+		t.Errorf("quotas = %+v, want 240 and 150", got.Quotas)
+	}
+	if !slices.Equal(got.Exclusions.Common, []string{"COMP1022Q"}) ||
+		!slices.Equal(got.Exclusions.Only["COMP1021"], []string{"COMP1022P"}) ||
+		!slices.Equal(got.Exclusions.Only["COMP1022P"], []string{"COMP1021"}) {
+		t.Errorf("exclusions = %+v", got.Exclusions)
+	}
+	if !slices.Equal(got.SectionTimes.Common, []string{"TuTh 03:00PM - 04:20PM"}) ||
+		!slices.Equal(got.SectionTimes.Only["COMP1021"], []string{"Mo 09:00AM - 10:50AM"}) ||
+		len(got.SectionTimes.Only["COMP1022P"]) != 0 {
+		t.Errorf("section times = %+v", got.SectionTimes)
+	}
+	if !slices.Equal(got.Instructors.Common, []string{"LAM, Gibson"}) ||
+		!slices.Equal(got.Instructors.Only["COMP1021"], []string{"TA, Alice", "TA, Bob"}) {
+		t.Errorf("instructors = %+v", got.Instructors)
+	}
+}
+
+func TestHandleCompareCourses_Errors(t *testing.T) {
+	tests := []struct {
+		target string
+		want   int
+	}{
+		{"/v1/compare", http.StatusBadRequest},
+		{"/v1/compare?courses=COMP1021", http.StatusBadRequest},
+		{"/v1/compare?courses=COMP1021,1234", http.StatusBadRequest},
+		{"/v1/compare?courses=COMP1021,ISOM1021", http.StatusBadRequest},
+		{"/v1/compare?courses=A1,B2,C3,D4,E5,F6", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		a := fixtureApp()
+		c, rec := setupHandlerTest(http.MethodGet, tt.target, a)
+		if err := a.HandleCompareCourses(c); err != nil {
+			t.Fatalf("HandleCompareCourses() error: %v", err)
+		}
+		if rec.Code != tt.want {
+			t.Errorf("GET %s status = %d, want %d", tt.target, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/labstack/echo/v4"
+)
+
+// contentEncodings lists the supported encodings in order of preference when
+// a client accepts several with equal quality.
+var contentEncodings = []string{"zstd", "br", "gzip"}
+
+// negotiateEncoding picks a content coding from an Accept-Encoding header,
+// returning "" when the response should be sent uncompressed.
+func negotiateEncoding(header string) string {
+	if header == "" {
+		return ""
+	}
+	quality := make(map[string]float64)
+	for part := range strings.SplitSeq(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		quality[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+	best, bestQ := "", 0.0
+	for _, enc := range contentEncodings {
+		q, ok := quality[enc]
+		if !ok {
+			q, ok = quality["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
+
+func newEncoder(enc string, w io.Writer) io.WriteCloser {
+	switch enc {
+	case "zstd":
+		// Only invalid options make NewWriter fail.
+		zw, _ := zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.SpeedDefault))
+		return zw
+	case "br":
+		return brotli.NewWriterLevel(w, brotli.DefaultCompression)
+	}
+	return gzip.NewWriter(w)
+}
+
+// compression negotiates a content coding with the client and compresses
+// response bodies with it. Entity tags of compressed responses carry the
+// coding as a suffix so that they stay distinct from the identity
+// representation; the suffix is stripped from If-None-Match before handlers
+// compare it.
+func compression(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		res := c.Response()
+		res.Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+		enc := negotiateEncoding(c.Request().Header.Get(echo.HeaderAcceptEncoding))
+		if enc == "" {
+			return next(c)
+		}
+		if inm := c.Request().Header.Get("If-None-Match"); inm != "" {
+			c.Request().Header.Set("If-None-Match", strings.ReplaceAll(inm, "-"+enc+`"`, `"`))
+		}
+		cw := &compressWriter{ResponseWriter: res.Writer, encoding: enc}
+		res.Writer = cw
+		defer func() {
+			cw.Close()
+			res.Writer = cw.ResponseWriter
+		}()
+		return next(c)
+	}
+}
+
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	encoder     io.WriteCloser
+	wroteHeader bool
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	header := w.Header()
+	if header.Get(echo.HeaderContentEncoding) == "" {
+		// A 304 carries the validator the client would have received, so the
+		// suffix is added even though no body is compressed.
+		if etag := header.Get("ETag"); strings.HasSuffix(etag, `"`) && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", strings.TrimSuffix(etag, `"`)+"-"+w.encoding+`"`)
+		}
+		if code != http.StatusNotModified && code != http.StatusNoContent {
+			header.Set(echo.HeaderContentEncoding, w.encoding)
+			header.Del(echo.HeaderContentLength)
+			w.encoder = newEncoder(w.encoding, w.ResponseWriter)
+		}
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.encoder == nil {
+		return w.ResponseWriter.Write(b)
+	}
+	return w.encoder.Write(b)
+}
+
+// Flush pushes buffered compressed data to the client, keeping streamed
+// exports incremental.
+func (w *compressWriter) Flush() {
+	if f, ok := w.encoder.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) Close() error {
+	if w.encoder == nil {
+		return nil
+	}
+	return w.encoder.Close()
+}
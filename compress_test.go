@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+	"github.com/labstack/echo/v4"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, deflate, br", "br"},
+		{"gzip, br, zstd", "zstd"},
+		{"br;q=0.5, gzip", "gzip"},
+		{"zstd;q=0, gzip", "gzip"},
+		{"*", "zstd"},
+		{"*;q=0.1, gzip;q=0.5", "gzip"},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func decode(t *testing.T, enc string, body []byte) []byte {
+	t.Helper()
+	var r io.Reader
+	switch enc {
+	case "gzip":
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("gzip.NewReader() error: %v", err)
+		}
+		r = gr
+	case "br":
+		r = brotli.NewReader(bytes.NewReader(body))
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("zstd.NewReader() error: %v", err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return body
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("decoding %s body: %v", enc, err)
+	}
+	return b
+}
+
+func TestCompression(t *testing.T) {
+	a := fixtureApp()
+	e := echo.New()
+	e.Use(compression)
+	e.GET("/v1/courses", a.HandleGetCourses)
+
+	plain := httptest.NewRecorder()
+	e.ServeHTTP(plain, httptest.NewRequest(http.MethodGet, "/v1/courses", nil))
+
+	for _, enc := range []string{"gzip", "br", "zstd"} {
+		t.Run(enc, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/courses", nil)
+			req.Header.Set(echo.HeaderAcceptEncoding, enc)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if got := rec.Header().Get(echo.HeaderContentEncoding); got != enc {
+				t.Errorf("Content-Encoding = %q, want %q", got, enc)
+			}
+			if got := rec.Header().Get(echo.HeaderVary); got != echo.HeaderAcceptEncoding {
+				t.Errorf("Vary = %q, want %q", got, echo.HeaderAcceptEncoding)
+			}
+			if got := decode(t, enc, rec.Body.Bytes()); !bytes.Equal(got, plain.Body.Bytes()) {
+				t.Errorf("decoded body = %q, want %q", got, plain.Body.Bytes())
+			}
+			etag := rec.Header().Get("ETag")
+			if want := plain.Header().Get("ETag")[:len(plain.Header().Get("ETag"))-1] + "-" + enc + `"`; etag != want {
+				t.Errorf("ETag = %s, want %s", etag, want)
+			}
+
+			req = httptest.NewRequest(http.MethodGet, "/v1/courses", nil)
+			req.Header.Set(echo.HeaderAcceptEncoding, enc)
+			req.Header.Set("If-None-Match", etag)
+			rec = httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if rec.Code != http.StatusNotModified {
+				t.Errorf("status with compressed ETag = %d, want %d", rec.Code, http.StatusNotModified)
+			}
+			if rec.Body.Len() != 0 || rec.Header().Get(echo.HeaderContentEncoding) != "" {
+				t.Errorf("304 response carried an encoded body")
+			}
+		})
+	}
+}
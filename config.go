@@ -0,0 +1,424 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/robfig/cron/v3"
+	"gopkg.in/yaml.v3"
+)
+
+type config struct {
+	Port             string
+	MetricsPort      string
+	BaseURL          string
+	RefreshInterval  time.Duration
+	OTLPEndpoint     string
+	ValidateRequests bool
+	GRPCPort         string
+	Precache         bool
+	RefreshSchedule  string
+	CalendarFile     string
+	SemesterRange    string
+	SnapshotDir      string
+	Dataset          string
+	Database         string
+	// Calendar is loaded from CalendarFile; nil selects defaultCalendar.
+	Calendar *academicCalendar
+
+	// AddDropPeriod and AddDropRefreshInterval tune the calendar refresh
+	// schedule.
+	AddDropPeriod          time.Duration
+	AddDropRefreshInterval time.Duration
+
+	// ConfigFile and PrintConfig control how the configuration is loaded
+	// rather than how the app runs, so they are not settings themselves.
+	ConfigFile  string
+	PrintConfig bool
+	// Command holds the subcommand to run instead of the servers, followed
+	// by its arguments.
+	Command []string
+}
+
+func defaultConfig() config {
+	return config{
+		Port:            ":8080",
+		MetricsPort:     ":2112",
+		GRPCPort:        ":9090",
+		BaseURL:         "https://w5.ab.ust.hk/wcq/cgi-bin",
+		RefreshInterval: 7 * 24 * time.Hour,
+
+		AddDropPeriod:          14 * 24 * time.Hour,
+		AddDropRefreshInterval: time.Hour,
+	}
+}
+
+// setting describes a configuration value that can be given in the config
+// file, as an environment variable or as a command-line flag.
+type setting struct {
+	name  string // key in the config file
+	env   string
+	usage string
+	set   func(cfg *config, v string) error
+	get   func(cfg config) string
+	// secret marks URL settings whose credentials must not be printed.
+	secret bool
+}
+
+var settings = []setting{
+	{
+		name: "port", env: "PORT", usage: "HTTP listen port or host:port",
+		set: func(cfg *config, v string) (err error) { cfg.Port, err = parseListenAddr(v); return },
+		get: func(cfg config) string { return cfg.Port },
+	},
+	{
+		name: "metrics_port", env: "METRICS_PORT", usage: "Prometheus metrics listen port or host:port",
+		set: func(cfg *config, v string) (err error) { cfg.MetricsPort, err = parseListenAddr(v); return },
+		get: func(cfg config) string { return cfg.MetricsPort },
+	},
+	{
+		name: "grpc_port", env: "GRPC_PORT", usage: "gRPC listen port or host:port",
+		set: func(cfg *config, v string) (err error) { cfg.GRPCPort, err = parseListenAddr(v); return },
+		get: func(cfg config) string { return cfg.GRPCPort },
+	},
+	{
+		name: "base_url", env: "BASE_URL", usage: "base URL of the class schedule site",
+		set: func(cfg *config, v string) (err error) {
+			cfg.BaseURL, err = parseHTTPURL(v)
+			cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+			return
+		},
+		get:    func(cfg config) string { return cfg.BaseURL },
+		secret: true,
+	},
+	{
+		name: "refresh_interval", env: "REFRESH_INTERVAL", usage: "interval between full catalogue refreshes",
+		set: func(cfg *config, v string) (err error) { cfg.RefreshInterval, err = parsePositiveDuration(v); return },
+		get: func(cfg config) string { return cfg.RefreshInterval.String() },
+	},
+	{
+		name: "refresh_schedule", env: "REFRESH_SCHEDULE",
+		usage: `cron expression for full catalogue refreshes, or "calendar" to refresh every add_drop_refresh_interval during the add/drop period and every refresh_interval otherwise`,
+		set: func(cfg *config, v string) error {
+			if v != "" && v != refreshScheduleCalendar {
+				if _, err := cron.ParseStandard(v); err != nil {
+					return err
+				}
+			}
+			cfg.RefreshSchedule = v
+			return nil
+		},
+		get: func(cfg config) string { return cfg.RefreshSchedule },
+	},
+	{
+		name: "add_drop_period", env: "ADD_DROP_PERIOD", usage: "length of the add/drop period at the start of each semester",
+		set: func(cfg *config, v string) (err error) { cfg.AddDropPeriod, err = parsePositiveDuration(v); return },
+		get: func(cfg config) string { return cfg.AddDropPeriod.String() },
+	},
+	{
+		name: "add_drop_refresh_interval", env: "ADD_DROP_REFRESH_INTERVAL", usage: "interval between refreshes during the add/drop period",
+		set: func(cfg *config, v string) (err error) {
+			cfg.AddDropRefreshInterval, err = parsePositiveDuration(v)
+			return
+		},
+		get: func(cfg config) string { return cfg.AddDropRefreshInterval.String() },
+	},
+	{
+		name: "otlp_endpoint", env: "OTEL_EXPORTER_OTLP_ENDPOINT", usage: "OTLP/HTTP endpoint to export traces to",
+		set:    func(cfg *config, v string) (err error) { cfg.OTLPEndpoint, err = parseHTTPURL(v); return },
+		get:    func(cfg config) string { return cfg.OTLPEndpoint },
+		secret: true,
+	},
+	{
+		name: "calendar_file", env: "CALENDAR_FILE", usage: "YAML or TOML file describing the academic calendar",
+		set: func(cfg *config, v string) (err error) {
+			cfg.CalendarFile, cfg.Calendar = v, nil
+			if v != "" {
+				cfg.Calendar, err = loadCalendar(v)
+			}
+			return
+		},
+		get: func(cfg config) string { return cfg.CalendarFile },
+	},
+	{
+		name: "semester_range", env: "SEMESTER_RANGE", usage: "semesters listed by /v1/semesters, as FIRST-LAST codes such as 2410-2540, instead of those linked upstream",
+		set: func(cfg *config, v string) error {
+			cfg.SemesterRange = v
+			return nil
+		},
+		get: func(cfg config) string { return cfg.SemesterRange },
+	},
+	{
+		name: "snapshot_dir", env: "SNAPSHOT_DIR", usage: "directory to persist a snapshot of each crawled semester in, for offering history",
+		set: func(cfg *config, v string) error {
+			cfg.SnapshotDir = v
+			return nil
+		},
+		get: func(cfg config) string { return cfg.SnapshotDir },
+	},
+	{
+		name: "dataset", env: "DATASET", usage: "dataset directory or archive written by the export command to serve read-only, without upstream access",
+		set: func(cfg *config, v string) error {
+			cfg.Dataset = v
+			return nil
+		},
+		get: func(cfg config) string { return cfg.Dataset },
+	},
+	{
+		name: "database", env: "DATABASE", usage: "SQLite database file to store every crawled semester in, for SQL queries and older semesters",
+		set: func(cfg *config, v string) error {
+			cfg.Database = v
+			return nil
+		},
+		get: func(cfg config) string { return cfg.Database },
+	},
+	{
+		name: "validate_requests", env: "VALIDATE_REQUESTS", usage: "validate requests against the OpenAPI spec",
+		set: func(cfg *config, v string) (err error) { cfg.ValidateRequests, err = strconv.ParseBool(v); return },
+		get: func(cfg config) string { return strconv.FormatBool(cfg.ValidateRequests) },
+	},
+	{
+		name: "precache", env: "PRECACHE", usage: "pre-cache current semester courses on startup",
+		set: func(cfg *config, v string) (err error) { cfg.Precache, err = strconv.ParseBool(v); return },
+		get: func(cfg config) string { return strconv.FormatBool(cfg.Precache) },
+	},
+}
+
+// flagName returns the command-line flag for a setting, which uses dashes
+// where the config file key uses underscores.
+func (s setting) flagName() string {
+	return strings.ReplaceAll(s.name, "_", "-")
+}
+
+// boolSetting reports whether a setting is a boolean, which lets its flag be
+// given without a value.
+func (s setting) boolSetting() bool {
+	v := s.get(defaultConfig())
+	return v == "true" || v == "false"
+}
+
+// loadConfig builds the configuration from, in increasing order of
+// precedence, the defaults, the config file, environment variables and the
+// command-line arguments. Invalid values are reported rather than ignored.
+func loadConfig(args []string) (config, error) {
+	cfg := defaultConfig()
+
+	fs := flag.NewFlagSet("courseinfo", flag.ContinueOnError)
+	fs.StringVar(&cfg.ConfigFile, "config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	fs.BoolVar(&cfg.PrintConfig, "print-config", false, "print the effective configuration and exit")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [flags] [command [arguments]]\n\nFlags:\n", fs.Name())
+		fs.PrintDefaults()
+		printCommands(fs.Output())
+	}
+	type override struct {
+		setting setting
+		value   string
+	}
+	var overrides []override
+	for _, s := range settings {
+		usage := fmt.Sprintf("%s (env %s)", s.usage, s.env)
+		if def := s.get(cfg); def != "" {
+			usage = fmt.Sprintf("%s (env %s, default %s)", s.usage, s.env, def)
+		}
+		record := func(v string) error {
+			overrides = append(overrides, override{s, v})
+			return nil
+		}
+		if s.boolSetting() {
+			fs.BoolFunc(s.flagName(), usage, record)
+		} else {
+			fs.Func(s.flagName(), usage, record)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+	if fs.NArg() > 0 {
+		if _, ok := findCommand(fs.Arg(0)); !ok {
+			return cfg, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+		}
+		cfg.Command = fs.Args()
+	}
+
+	if cfg.ConfigFile != "" {
+		if err := cfg.loadFile(cfg.ConfigFile); err != nil {
+			return cfg, err
+		}
+	}
+	for _, s := range settings {
+		if v := os.Getenv(s.env); v != "" {
+			if err := s.set(&cfg, v); err != nil {
+				return cfg, fmt.Errorf("invalid %s %q: %w", s.env, v, err)
+			}
+		}
+	}
+	for _, o := range overrides {
+		if err := o.setting.set(&cfg, o.value); err != nil {
+			return cfg, fmt.Errorf("invalid -%s %q: %w", o.setting.flagName(), o.value, err)
+		}
+	}
+	return cfg, cfg.validate()
+}
+
+// loadFile applies the settings in a YAML or TOML config file. Unknown keys
+// are rejected so that typos do not go unnoticed.
+func (cfg *config) loadFile(name string) error {
+	values := make(map[string]any)
+	if err := decodeConfigFile(name, &values); err != nil {
+		return err
+	}
+	for key := range values {
+		if findSetting(key) == nil {
+			return fmt.Errorf("%s: %w: %q", name, ErrUnknownConfigKey, key)
+		}
+	}
+	// Apply in table order so that errors are reported deterministically.
+	for _, s := range settings {
+		raw, ok := values[s.name]
+		if !ok {
+			continue
+		}
+		var v string
+		switch raw := raw.(type) {
+		case string, bool, int, int64, float64:
+			v = fmt.Sprint(raw)
+		default:
+			return fmt.Errorf("%s: invalid %s: must be a scalar value", name, s.name)
+		}
+		if err := s.set(cfg, v); err != nil {
+			return fmt.Errorf("%s: invalid %s %q: %w", name, s.name, v, err)
+		}
+	}
+	return nil
+}
+
+// decodeConfigFile decodes a YAML or TOML file, chosen by its extension,
+// into v, rejecting keys that do not match a field of v.
+func decodeConfigFile(name string, v any) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	switch ext := strings.ToLower(filepath.Ext(name)); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err = dec.Decode(v); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case ".toml":
+		var md toml.MetaData
+		md, err = toml.Decode(string(data), v)
+		if undecoded := md.Undecoded(); err == nil && len(undecoded) > 0 {
+			err = fmt.Errorf("%w: %q", ErrUnknownConfigKey, undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedConfigFormat, ext)
+	}
+	if err != nil {
+		return fmt.Errorf("parsing config file %s: %w", name, err)
+	}
+	return nil
+}
+
+// validate checks settings that depend on each other once all of them are
+// known.
+func (cfg config) validate() error {
+	if cfg.SemesterRange != "" {
+		cal := cfg.Calendar
+		if cal == nil {
+			cal = defaultCalendar()
+		}
+		if _, err := cal.semesterRange(cfg.SemesterRange); err != nil {
+			return fmt.Errorf("invalid semester_range: %w", err)
+		}
+	}
+	return nil
+}
+
+func findSetting(name string) *setting {
+	for i := range settings {
+		if settings[i].name == name {
+			return &settings[i]
+		}
+	}
+	return nil
+}
+
+// writeTo prints the effective configuration as YAML that can be used as a
+// config file, with credentials in URLs redacted.
+func (cfg config) writeTo(w io.Writer) error {
+	doc := &yaml.Node{Kind: yaml.MappingNode}
+	for _, s := range settings {
+		v := s.get(cfg)
+		if s.secret && v != "" {
+			if u, err := url.Parse(v); err == nil {
+				v = u.Redacted()
+			}
+		}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: s.name}
+		value := &yaml.Node{Kind: yaml.ScalarNode, Value: v}
+		if !s.boolSetting() {
+			value.Style = yaml.DoubleQuotedStyle
+		}
+		doc.Content = append(doc.Content, key, value)
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// parseListenAddr accepts a bare port number, as the PORT variables always
+// have, or a host:port address, and returns it in the form net.Listen takes.
+func parseListenAddr(v string) (string, error) {
+	if !strings.Contains(v, ":") {
+		v = ":" + v
+	}
+	_, port, err := net.SplitHostPort(v)
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return "", errors.New("port must be a number between 1 and 65535")
+	}
+	return v, nil
+}
+
+func parsePositiveDuration(v string) (time.Duration, error) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, errors.New("must be positive")
+	}
+	return d, nil
+}
+
+// parseHTTPURL checks that v is an absolute http or https URL.
+func parseHTTPURL(v string) (string, error) {
+	u, err := url.Parse(v)
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.New("must be an absolute http or https URL")
+	}
+	return v, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// courseCode is a course code split into its parts. Its canonical form, as
+// returned by String, is the upper-case department prefix, the number and
+// any letter suffix run together, e.g. COMP4901X.
+type courseCode struct {
+	Department string
+	Number     string
+	Suffix     string
+}
+
+func (c courseCode) String() string {
+	return c.Department + c.Number + c.Suffix
+}
+
+var courseCodePattern = regexp.MustCompile(`^([A-Z]+)([0-9]+)([A-Z]*)$`)
+
+// courseCodeSeparators are dropped from user spellings such as "comp 1021"
+// or "COMP-1021".
+var courseCodeSeparators = strings.NewReplacer("-", "", "_", "", ".", "")
+
+// parseCourseCode accepts a course code in any common spelling and returns
+// it in canonical form.
+func parseCourseCode(raw string) (courseCode, error) {
+	s := strings.ToUpper(courseCodeSeparators.Replace(raw))
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	m := courseCodePattern.FindStringSubmatch(s)
+	if m == nil {
+		return courseCode{}, ErrInvalidCourseCode
+	}
+	return courseCode{Department: m[1], Number: m[2], Suffix: m[3]}, nil
+}
+
+// courseCodeMention matches course codes mentioned in free text, such as
+// the co-listing attribute of a course.
+var courseCodeMention = regexp.MustCompile(`\b[A-Z]{2,5} ?[0-9]{4}[A-Z]?\b`)
+
+// mentionedCourseCodes returns the canonical codes of the courses mentioned
+// in s, in order of first appearance.
+func mentionedCourseCodes(s string) []string {
+	var codes []string
+	for _, m := range courseCodeMention.FindAllString(s, -1) {
+		code, err := parseCourseCode(m)
+		if err != nil {
+			continue
+		}
+		if c := code.String(); !slices.Contains(codes, c) {
+			codes = append(codes, c)
+		}
+	}
+	return codes
+}
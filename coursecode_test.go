@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestParseCourseCode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"COMP1021", "COMP1021"},
+		{"comp 1021", "COMP1021"},
+		{" Comp1022p ", "COMP1022P"},
+		{"COMP-4901x", "COMP4901X"},
+		{"isom_3360", "ISOM3360"},
+		{"LANG 1002 A", "LANG1002A"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseCourseCode(tt.in)
+			if err != nil {
+				t.Fatalf("parseCourseCode(%q) error: %v", tt.in, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("parseCourseCode(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCourseCode_Invalid(t *testing.T) {
+	for _, in := range []string{"", "COMP", "1021", "COMP10 21X1", "CÖMP1021"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := parseCourseCode(in); !errors.Is(err, ErrInvalidCourseCode) {
+				t.Errorf("parseCourseCode(%q) error = %v, want ErrInvalidCourseCode", in, err)
+			}
+		})
+	}
+}
+
+func TestMentionedCourseCodes(t *testing.T) {
+	got := mentionedCourseCodes("ISOM 3360, COMP4901X and ISOM3360 (Fall only)")
+	want := []string{"ISOM3360", "COMP4901X"}
+	if !slices.Equal(got, want) {
+		t.Errorf("mentionedCourseCodes() = %v, want %v", got, want)
+	}
+}
+
+func TestHandleGetCourse_CrossListedAlias(t *testing.T) {
+	a := testApp()
+	a.remember(&CourseParsingResult{Code: "COMP4332", Course: &Course{
+		Code:       "COMP4332",
+		Title:      "Big Data Mining and Management",
+		CoListWith: []string{"RMBI4310"},
+	}})
+
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/courses/rmbi-4310", a)
+	c.SetParamNames("course")
+	c.SetParamValues("rmbi-4310")
+
+	if err := a.HandleGetCourse(c); err != nil {
+		t.Fatalf("HandleGetCourse() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var course Course
+	if err := json.Unmarshal(rec.Body.Bytes(), &course); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if course.Code != "COMP4332" {
+		t.Errorf("code = %q, want %q", course.Code, "COMP4332")
+	}
+	if got, want := rec.Header().Get(echo.HeaderLastModified), a.modified["COMP4332"].UTC().Format(http.TimeFormat); got != want {
+		t.Errorf("Last-Modified = %q, want that of COMP4332, %q", got, want)
+	}
+
+	// The alias also works once the course is archived by a rollover.
+	a.config.BaseURL = "http://127.0.0.1:1"
+	a.endpoint = a.config.BaseURL + "/2510"
+	rollOver(a, "2520")
+	c, rec = setupHandlerTest(http.MethodGet, "/v1/courses/RMBI4310?semester=2510", a)
+	c.SetParamNames("course")
+	c.SetParamValues("RMBI4310")
+	if err := a.HandleGetCourse(c); err != nil {
+		t.Fatalf("HandleGetCourse() error: %v", err)
+	}
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "COMP4332") {
+		t.Errorf("archived alias status = %d, body = %s, want COMP4332", rec.Code, rec.Body)
+	}
+}
+
+func TestRemember_DroppedCoListing(t *testing.T) {
+	a := testApp()
+	a.remember(&CourseParsingResult{Code: "COMP4332", Course: &Course{Code: "COMP4332", CoListWith: []string{"RMBI4310"}}})
+	a.remember(&CourseParsingResult{Code: "COMP4332", Course: &Course{Code: "COMP4332", CoListWith: []string{"ISOM4310"}}})
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if _, ok := a.cachedCourseLocked("RMBI4310"); ok {
+		t.Error("RMBI4310 still resolves after COMP4332 dropped the co-listing")
+	}
+	if course, ok := a.cachedCourseLocked("ISOM4310"); !ok || course.Code != "COMP4332" {
+		t.Errorf("ISOM4310 resolves to %v, want COMP4332", course)
+	}
+}
@@ -0,0 +1,641 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: courseinfopb/courseinfo.proto
+
+package courseinfopb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CourseChange_Type int32
+
+const (
+	CourseChange_TYPE_UNSPECIFIED CourseChange_Type = 0
+	CourseChange_TYPE_ADDED       CourseChange_Type = 1
+	CourseChange_TYPE_UPDATED     CourseChange_Type = 2
+	// The course is no longer in the catalogue, after a recrawl or a
+	// semester rollover; course is its last version.
+	CourseChange_TYPE_REMOVED CourseChange_Type = 3
+)
+
+// Enum value maps for CourseChange_Type.
+var (
+	CourseChange_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_ADDED",
+		2: "TYPE_UPDATED",
+		3: "TYPE_REMOVED",
+	}
+	CourseChange_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_ADDED":       1,
+		"TYPE_UPDATED":     2,
+		"TYPE_REMOVED":     3,
+	}
+)
+
+func (x CourseChange_Type) Enum() *CourseChange_Type {
+	p := new(CourseChange_Type)
+	*p = x
+	return p
+}
+
+func (x CourseChange_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CourseChange_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_courseinfopb_courseinfo_proto_enumTypes[0].Descriptor()
+}
+
+func (CourseChange_Type) Type() protoreflect.EnumType {
+	return &file_courseinfopb_courseinfo_proto_enumTypes[0]
+}
+
+func (x CourseChange_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CourseChange_Type.Descriptor instead.
+func (CourseChange_Type) EnumDescriptor() ([]byte, []int) {
+	return file_courseinfopb_courseinfo_proto_rawDescGZIP(), []int{7, 0}
+}
+
+type GetCourseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCourseRequest) Reset() {
+	*x = GetCourseRequest{}
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCourseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCourseRequest) ProtoMessage() {}
+
+func (x *GetCourseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCourseRequest.ProtoReflect.Descriptor instead.
+func (*GetCourseRequest) Descriptor() ([]byte, []int) {
+	return file_courseinfopb_courseinfo_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetCourseRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ListCoursesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Department code such as COMP. Empty lists every cached course.
+	Department    string `protobuf:"bytes,1,opt,name=department,proto3" json:"department,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCoursesRequest) Reset() {
+	*x = ListCoursesRequest{}
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCoursesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCoursesRequest) ProtoMessage() {}
+
+func (x *ListCoursesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCoursesRequest.ProtoReflect.Descriptor instead.
+func (*ListCoursesRequest) Descriptor() ([]byte, []int) {
+	return file_courseinfopb_courseinfo_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListCoursesRequest) GetDepartment() string {
+	if x != nil {
+		return x.Department
+	}
+	return ""
+}
+
+type GetSemesterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Semester code such as 2510. Empty or "current" selects the current
+	// semester, and "next" the one after it.
+	Code          string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSemesterRequest) Reset() {
+	*x = GetSemesterRequest{}
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSemesterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSemesterRequest) ProtoMessage() {}
+
+func (x *GetSemesterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSemesterRequest.ProtoReflect.Descriptor instead.
+func (*GetSemesterRequest) Descriptor() ([]byte, []int) {
+	return file_courseinfopb_courseinfo_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetSemesterRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type WatchChangesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Department code to filter changes by. Empty watches every department.
+	Department    string `protobuf:"bytes,1,opt,name=department,proto3" json:"department,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchChangesRequest) Reset() {
+	*x = WatchChangesRequest{}
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchChangesRequest) ProtoMessage() {}
+
+func (x *WatchChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchChangesRequest.ProtoReflect.Descriptor instead.
+func (*WatchChangesRequest) Descriptor() ([]byte, []int) {
+	return file_courseinfopb_courseinfo_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WatchChangesRequest) GetDepartment() string {
+	if x != nil {
+		return x.Department
+	}
+	return ""
+}
+
+type Course struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Credits       float64                `protobuf:"fixed64,3,opt,name=credits,proto3" json:"credits,omitempty"`
+	Instructors   []*Instructor          `protobuf:"bytes,4,rep,name=instructors,proto3" json:"instructors,omitempty"`
+	Sections      []string               `protobuf:"bytes,5,rep,name=sections,proto3" json:"sections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Course) Reset() {
+	*x = Course{}
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Course) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Course) ProtoMessage() {}
+
+func (x *Course) ProtoReflect() protoreflect.Message {
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Course.ProtoReflect.Descriptor instead.
+func (*Course) Descriptor() ([]byte, []int) {
+	return file_courseinfopb_courseinfo_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Course) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Course) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Course) GetCredits() float64 {
+	if x != nil {
+		return x.Credits
+	}
+	return 0
+}
+
+func (x *Course) GetInstructors() []*Instructor {
+	if x != nil {
+		return x.Instructors
+	}
+	return nil
+}
+
+func (x *Course) GetSections() []string {
+	if x != nil {
+		return x.Sections
+	}
+	return nil
+}
+
+type Instructor struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Name  string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Section codes taught by the instructor.
+	Sections      []string `protobuf:"bytes,2,rep,name=sections,proto3" json:"sections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Instructor) Reset() {
+	*x = Instructor{}
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Instructor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Instructor) ProtoMessage() {}
+
+func (x *Instructor) ProtoReflect() protoreflect.Message {
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Instructor.ProtoReflect.Descriptor instead.
+func (*Instructor) Descriptor() ([]byte, []int) {
+	return file_courseinfopb_courseinfo_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Instructor) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Instructor) GetSections() []string {
+	if x != nil {
+		return x.Sections
+	}
+	return nil
+}
+
+type Semester struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Code   string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Name   string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Year   string                 `protobuf:"bytes,3,opt,name=year,proto3" json:"year,omitempty"`
+	Cohort string                 `protobuf:"bytes,4,opt,name=cohort,proto3" json:"cohort,omitempty"`
+	// First and last day of the semester, as YYYY-MM-DD.
+	Start         string `protobuf:"bytes,5,opt,name=start,proto3" json:"start,omitempty"`
+	End           string `protobuf:"bytes,6,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Semester) Reset() {
+	*x = Semester{}
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Semester) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Semester) ProtoMessage() {}
+
+func (x *Semester) ProtoReflect() protoreflect.Message {
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Semester.ProtoReflect.Descriptor instead.
+func (*Semester) Descriptor() ([]byte, []int) {
+	return file_courseinfopb_courseinfo_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Semester) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Semester) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Semester) GetYear() string {
+	if x != nil {
+		return x.Year
+	}
+	return ""
+}
+
+func (x *Semester) GetCohort() string {
+	if x != nil {
+		return x.Cohort
+	}
+	return ""
+}
+
+func (x *Semester) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *Semester) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+type CourseChange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          CourseChange_Type      `protobuf:"varint,1,opt,name=type,proto3,enum=courseinfo.v1.CourseChange_Type" json:"type,omitempty"`
+	Course        *Course                `protobuf:"bytes,2,opt,name=course,proto3" json:"course,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CourseChange) Reset() {
+	*x = CourseChange{}
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CourseChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CourseChange) ProtoMessage() {}
+
+func (x *CourseChange) ProtoReflect() protoreflect.Message {
+	mi := &file_courseinfopb_courseinfo_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CourseChange.ProtoReflect.Descriptor instead.
+func (*CourseChange) Descriptor() ([]byte, []int) {
+	return file_courseinfopb_courseinfo_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CourseChange) GetType() CourseChange_Type {
+	if x != nil {
+		return x.Type
+	}
+	return CourseChange_TYPE_UNSPECIFIED
+}
+
+func (x *CourseChange) GetCourse() *Course {
+	if x != nil {
+		return x.Course
+	}
+	return nil
+}
+
+var File_courseinfopb_courseinfo_proto protoreflect.FileDescriptor
+
+const file_courseinfopb_courseinfo_proto_rawDesc = "" +
+	"\n" +
+	"\x1dcourseinfopb/courseinfo.proto\x12\rcourseinfo.v1\"&\n" +
+	"\x10GetCourseRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"4\n" +
+	"\x12ListCoursesRequest\x12\x1e\n" +
+	"\n" +
+	"department\x18\x01 \x01(\tR\n" +
+	"department\"(\n" +
+	"\x12GetSemesterRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"5\n" +
+	"\x13WatchChangesRequest\x12\x1e\n" +
+	"\n" +
+	"department\x18\x01 \x01(\tR\n" +
+	"department\"\xa5\x01\n" +
+	"\x06Course\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x18\n" +
+	"\acredits\x18\x03 \x01(\x01R\acredits\x12;\n" +
+	"\vinstructors\x18\x04 \x03(\v2\x19.courseinfo.v1.InstructorR\vinstructors\x12\x1a\n" +
+	"\bsections\x18\x05 \x03(\tR\bsections\"<\n" +
+	"\n" +
+	"Instructor\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bsections\x18\x02 \x03(\tR\bsections\"\x86\x01\n" +
+	"\bSemester\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04year\x18\x03 \x01(\tR\x04year\x12\x16\n" +
+	"\x06cohort\x18\x04 \x01(\tR\x06cohort\x12\x14\n" +
+	"\x05start\x18\x05 \x01(\tR\x05start\x12\x10\n" +
+	"\x03end\x18\x06 \x01(\tR\x03end\"\xc5\x01\n" +
+	"\fCourseChange\x124\n" +
+	"\x04type\x18\x01 \x01(\x0e2 .courseinfo.v1.CourseChange.TypeR\x04type\x12-\n" +
+	"\x06course\x18\x02 \x01(\v2\x15.courseinfo.v1.CourseR\x06course\"P\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"TYPE_ADDED\x10\x01\x12\x10\n" +
+	"\fTYPE_UPDATED\x10\x02\x12\x10\n" +
+	"\fTYPE_REMOVED\x10\x032\xba\x02\n" +
+	"\n" +
+	"CourseInfo\x12C\n" +
+	"\tGetCourse\x12\x1f.courseinfo.v1.GetCourseRequest\x1a\x15.courseinfo.v1.Course\x12I\n" +
+	"\vListCourses\x12!.courseinfo.v1.ListCoursesRequest\x1a\x15.courseinfo.v1.Course0\x01\x12I\n" +
+	"\vGetSemester\x12!.courseinfo.v1.GetSemesterRequest\x1a\x17.courseinfo.v1.Semester\x12Q\n" +
+	"\fWatchChanges\x12\".courseinfo.v1.WatchChangesRequest\x1a\x1b.courseinfo.v1.CourseChange0\x01B)Z'github.com/hkust-cse/crapi/courseinfopbb\x06proto3"
+
+var (
+	file_courseinfopb_courseinfo_proto_rawDescOnce sync.Once
+	file_courseinfopb_courseinfo_proto_rawDescData []byte
+)
+
+func file_courseinfopb_courseinfo_proto_rawDescGZIP() []byte {
+	file_courseinfopb_courseinfo_proto_rawDescOnce.Do(func() {
+		file_courseinfopb_courseinfo_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_courseinfopb_courseinfo_proto_rawDesc), len(file_courseinfopb_courseinfo_proto_rawDesc)))
+	})
+	return file_courseinfopb_courseinfo_proto_rawDescData
+}
+
+var file_courseinfopb_courseinfo_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_courseinfopb_courseinfo_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_courseinfopb_courseinfo_proto_goTypes = []any{
+	(CourseChange_Type)(0),      // 0: courseinfo.v1.CourseChange.Type
+	(*GetCourseRequest)(nil),    // 1: courseinfo.v1.GetCourseRequest
+	(*ListCoursesRequest)(nil),  // 2: courseinfo.v1.ListCoursesRequest
+	(*GetSemesterRequest)(nil),  // 3: courseinfo.v1.GetSemesterRequest
+	(*WatchChangesRequest)(nil), // 4: courseinfo.v1.WatchChangesRequest
+	(*Course)(nil),              // 5: courseinfo.v1.Course
+	(*Instructor)(nil),          // 6: courseinfo.v1.Instructor
+	(*Semester)(nil),            // 7: courseinfo.v1.Semester
+	(*CourseChange)(nil),        // 8: courseinfo.v1.CourseChange
+}
+var file_courseinfopb_courseinfo_proto_depIdxs = []int32{
+	6, // 0: courseinfo.v1.Course.instructors:type_name -> courseinfo.v1.Instructor
+	0, // 1: courseinfo.v1.CourseChange.type:type_name -> courseinfo.v1.CourseChange.Type
+	5, // 2: courseinfo.v1.CourseChange.course:type_name -> courseinfo.v1.Course
+	1, // 3: courseinfo.v1.CourseInfo.GetCourse:input_type -> courseinfo.v1.GetCourseRequest
+	2, // 4: courseinfo.v1.CourseInfo.ListCourses:input_type -> courseinfo.v1.ListCoursesRequest
+	3, // 5: courseinfo.v1.CourseInfo.GetSemester:input_type -> courseinfo.v1.GetSemesterRequest
+	4, // 6: courseinfo.v1.CourseInfo.WatchChanges:input_type -> courseinfo.v1.WatchChangesRequest
+	5, // 7: courseinfo.v1.CourseInfo.GetCourse:output_type -> courseinfo.v1.Course
+	5, // 8: courseinfo.v1.CourseInfo.ListCourses:output_type -> courseinfo.v1.Course
+	7, // 9: courseinfo.v1.CourseInfo.GetSemester:output_type -> courseinfo.v1.Semester
+	8, // 10: courseinfo.v1.CourseInfo.WatchChanges:output_type -> courseinfo.v1.CourseChange
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_courseinfopb_courseinfo_proto_init() }
+func file_courseinfopb_courseinfo_proto_init() {
+	if File_courseinfopb_courseinfo_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_courseinfopb_courseinfo_proto_rawDesc), len(file_courseinfopb_courseinfo_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_courseinfopb_courseinfo_proto_goTypes,
+		DependencyIndexes: file_courseinfopb_courseinfo_proto_depIdxs,
+		EnumInfos:         file_courseinfopb_courseinfo_proto_enumTypes,
+		MessageInfos:      file_courseinfopb_courseinfo_proto_msgTypes,
+	}.Build()
+	File_courseinfopb_courseinfo_proto = out.File
+	file_courseinfopb_courseinfo_proto_goTypes = nil
+	file_courseinfopb_courseinfo_proto_depIdxs = nil
+}
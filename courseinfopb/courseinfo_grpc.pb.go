@@ -0,0 +1,256 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: courseinfopb/courseinfo.proto
+
+package courseinfopb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CourseInfo_GetCourse_FullMethodName    = "/courseinfo.v1.CourseInfo/GetCourse"
+	CourseInfo_ListCourses_FullMethodName  = "/courseinfo.v1.CourseInfo/ListCourses"
+	CourseInfo_GetSemester_FullMethodName  = "/courseinfo.v1.CourseInfo/GetSemester"
+	CourseInfo_WatchChanges_FullMethodName = "/courseinfo.v1.CourseInfo/WatchChanges"
+)
+
+// CourseInfoClient is the client API for CourseInfo service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CourseInfo serves the same course catalogue as the REST API.
+type CourseInfoClient interface {
+	// GetCourse returns a course, scraping its department on a cache miss.
+	GetCourse(ctx context.Context, in *GetCourseRequest, opts ...grpc.CallOption) (*Course, error)
+	// ListCourses streams every cached course, optionally for one department.
+	ListCourses(ctx context.Context, in *ListCoursesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Course], error)
+	// GetSemester describes a semester code, or the current semester.
+	GetSemester(ctx context.Context, in *GetSemesterRequest, opts ...grpc.CallOption) (*Semester, error)
+	// WatchChanges streams courses as they are added to or updated in the
+	// cache until the client cancels.
+	WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CourseChange], error)
+}
+
+type courseInfoClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCourseInfoClient(cc grpc.ClientConnInterface) CourseInfoClient {
+	return &courseInfoClient{cc}
+}
+
+func (c *courseInfoClient) GetCourse(ctx context.Context, in *GetCourseRequest, opts ...grpc.CallOption) (*Course, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Course)
+	err := c.cc.Invoke(ctx, CourseInfo_GetCourse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseInfoClient) ListCourses(ctx context.Context, in *ListCoursesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Course], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CourseInfo_ServiceDesc.Streams[0], CourseInfo_ListCourses_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListCoursesRequest, Course]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CourseInfo_ListCoursesClient = grpc.ServerStreamingClient[Course]
+
+func (c *courseInfoClient) GetSemester(ctx context.Context, in *GetSemesterRequest, opts ...grpc.CallOption) (*Semester, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Semester)
+	err := c.cc.Invoke(ctx, CourseInfo_GetSemester_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *courseInfoClient) WatchChanges(ctx context.Context, in *WatchChangesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CourseChange], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CourseInfo_ServiceDesc.Streams[1], CourseInfo_WatchChanges_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchChangesRequest, CourseChange]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CourseInfo_WatchChangesClient = grpc.ServerStreamingClient[CourseChange]
+
+// CourseInfoServer is the server API for CourseInfo service.
+// All implementations must embed UnimplementedCourseInfoServer
+// for forward compatibility.
+//
+// CourseInfo serves the same course catalogue as the REST API.
+type CourseInfoServer interface {
+	// GetCourse returns a course, scraping its department on a cache miss.
+	GetCourse(context.Context, *GetCourseRequest) (*Course, error)
+	// ListCourses streams every cached course, optionally for one department.
+	ListCourses(*ListCoursesRequest, grpc.ServerStreamingServer[Course]) error
+	// GetSemester describes a semester code, or the current semester.
+	GetSemester(context.Context, *GetSemesterRequest) (*Semester, error)
+	// WatchChanges streams courses as they are added to or updated in the
+	// cache until the client cancels.
+	WatchChanges(*WatchChangesRequest, grpc.ServerStreamingServer[CourseChange]) error
+	mustEmbedUnimplementedCourseInfoServer()
+}
+
+// UnimplementedCourseInfoServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCourseInfoServer struct{}
+
+func (UnimplementedCourseInfoServer) GetCourse(context.Context, *GetCourseRequest) (*Course, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCourse not implemented")
+}
+func (UnimplementedCourseInfoServer) ListCourses(*ListCoursesRequest, grpc.ServerStreamingServer[Course]) error {
+	return status.Errorf(codes.Unimplemented, "method ListCourses not implemented")
+}
+func (UnimplementedCourseInfoServer) GetSemester(context.Context, *GetSemesterRequest) (*Semester, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSemester not implemented")
+}
+func (UnimplementedCourseInfoServer) WatchChanges(*WatchChangesRequest, grpc.ServerStreamingServer[CourseChange]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchChanges not implemented")
+}
+func (UnimplementedCourseInfoServer) mustEmbedUnimplementedCourseInfoServer() {}
+func (UnimplementedCourseInfoServer) testEmbeddedByValue()                    {}
+
+// UnsafeCourseInfoServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CourseInfoServer will
+// result in compilation errors.
+type UnsafeCourseInfoServer interface {
+	mustEmbedUnimplementedCourseInfoServer()
+}
+
+func RegisterCourseInfoServer(s grpc.ServiceRegistrar, srv CourseInfoServer) {
+	// If the following call pancis, it indicates UnimplementedCourseInfoServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CourseInfo_ServiceDesc, srv)
+}
+
+func _CourseInfo_GetCourse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCourseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseInfoServer).GetCourse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CourseInfo_GetCourse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseInfoServer).GetCourse(ctx, req.(*GetCourseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseInfo_ListCourses_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListCoursesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CourseInfoServer).ListCourses(m, &grpc.GenericServerStream[ListCoursesRequest, Course]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CourseInfo_ListCoursesServer = grpc.ServerStreamingServer[Course]
+
+func _CourseInfo_GetSemester_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSemesterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CourseInfoServer).GetSemester(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CourseInfo_GetSemester_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CourseInfoServer).GetSemester(ctx, req.(*GetSemesterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CourseInfo_WatchChanges_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchChangesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CourseInfoServer).WatchChanges(m, &grpc.GenericServerStream[WatchChangesRequest, CourseChange]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CourseInfo_WatchChangesServer = grpc.ServerStreamingServer[CourseChange]
+
+// CourseInfo_ServiceDesc is the grpc.ServiceDesc for CourseInfo service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CourseInfo_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "courseinfo.v1.CourseInfo",
+	HandlerType: (*CourseInfoServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCourse",
+			Handler:    _CourseInfo_GetCourse_Handler,
+		},
+		{
+			MethodName: "GetSemester",
+			Handler:    _CourseInfo_GetSemester_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListCourses",
+			Handler:       _CourseInfo_ListCourses_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchChanges",
+			Handler:       _CourseInfo_WatchChanges_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "courseinfopb/courseinfo.proto",
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// datasetSchemaVersion is incremented whenever the layout of exported
+// datasets changes incompatibly.
+const datasetSchemaVersion = 1
+
+// Files making up a dataset, in the order they are written.
+const (
+	datasetManifestFile    = "manifest.json"
+	datasetSemesterFile    = "semester.json"
+	datasetDepartmentsFile = "departments.json"
+	datasetCoursesFile     = "courses.json"
+	datasetSectionsFile    = "sections.json"
+)
+
+// datasetManifest describes an exported dataset.
+type datasetManifest struct {
+	SchemaVersion int       `json:"schema_version"`
+	Semester      string    `json:"semester"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	Departments   int       `json:"departments"`
+	Courses       int       `json:"courses"`
+	Sections      int       `json:"sections"`
+}
+
+// datasetSection is a section of a course, flattened for analysis without
+// the enclosing course.
+type datasetSection struct {
+	Course string `json:"course"`
+	Section
+}
+
+// dataset is a static copy of the catalogue of one semester. Sections are
+// exported twice, within their courses and as a flat list; they are read
+// back from the courses.
+type dataset struct {
+	Manifest    datasetManifest
+	Semester    semester
+	Departments []department
+	Courses     []*Course
+}
+
+func newDataset(s semester, departments []department, courses []*Course, generatedAt time.Time) dataset {
+	sections := 0
+	for _, course := range courses {
+		sections += len(course.Schedule)
+	}
+	return dataset{
+		Manifest: datasetManifest{
+			SchemaVersion: datasetSchemaVersion,
+			Semester:      s.Code,
+			GeneratedAt:   generatedAt,
+			Departments:   len(departments),
+			Courses:       len(courses),
+			Sections:      sections,
+		},
+		Semester:    s,
+		Departments: departments,
+		Courses:     courses,
+	}
+}
+
+// isArchive reports whether a dataset path names a gzipped tar archive
+// rather than a directory.
+func isArchive(name string) bool {
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
+
+// files returns the encoded files of the dataset, by name.
+func (ds dataset) files() ([]string, map[string][]byte, error) {
+	var sections []datasetSection
+	for _, course := range ds.Courses {
+		for _, s := range course.Schedule {
+			sections = append(sections, datasetSection{Course: course.Code, Section: s})
+		}
+	}
+	if sections == nil {
+		sections = []datasetSection{}
+	}
+	contents := map[string]any{
+		datasetManifestFile:    ds.Manifest,
+		datasetSemesterFile:    ds.Semester,
+		datasetDepartmentsFile: ds.Departments,
+		datasetCoursesFile:     ds.Courses,
+		datasetSectionsFile:    sections,
+	}
+	names := []string{datasetManifestFile, datasetSemesterFile, datasetDepartmentsFile, datasetCoursesFile, datasetSectionsFile}
+	files := make(map[string][]byte, len(names))
+	for _, name := range names {
+		data, err := json.MarshalIndent(contents[name], "", "  ")
+		if err != nil {
+			return nil, nil, err
+		}
+		files[name] = append(data, '\n')
+	}
+	return names, files, nil
+}
+
+// writeDataset writes ds to a directory, or to a gzipped tar archive if out
+// ends in .tar.gz or .tgz.
+func writeDataset(out string, ds dataset) error {
+	names, files, err := ds.files()
+	if err != nil {
+		return err
+	}
+	if !isArchive(out) {
+		if err := os.MkdirAll(out, 0o755); err != nil {
+			return fmt.Errorf("creating dataset directory: %w", err)
+		}
+		for _, name := range names {
+			if err := os.WriteFile(filepath.Join(out, name), files[name], 0o644); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(files[name])),
+			ModTime: ds.Manifest.GeneratedAt,
+		}
+		if err = tw.WriteHeader(hdr); err != nil {
+			break
+		}
+		if _, err = tw.Write(files[name]); err != nil {
+			break
+		}
+	}
+	err = errors.Join(err, tw.Close(), gz.Close())
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// readDatasetFiles returns the files of a dataset directory or archive, by
+// name.
+func readDatasetFiles(name string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	if !isArchive(name) {
+		entries, err := os.ReadDir(name)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(name, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			files[entry.Name()] = data
+		}
+		return files, nil
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Base(hdr.Name)] = data
+	}
+}
+
+// readDataset loads a dataset written by writeDataset.
+func readDataset(name string) (dataset, error) {
+	files, err := readDatasetFiles(name)
+	if err != nil {
+		return dataset{}, fmt.Errorf("reading dataset: %w", err)
+	}
+	var ds dataset
+	decode := func(file string, v any) error {
+		data, ok := files[file]
+		if !ok {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidDataset, file, os.ErrNotExist)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidDataset, file, err)
+		}
+		return nil
+	}
+	if err := decode(datasetManifestFile, &ds.Manifest); err != nil {
+		return dataset{}, err
+	}
+	if ds.Manifest.SchemaVersion != datasetSchemaVersion {
+		return dataset{}, fmt.Errorf("%w: schema version %d, want %d", ErrInvalidDataset, ds.Manifest.SchemaVersion, datasetSchemaVersion)
+	}
+	for _, f := range []struct {
+		name string
+		v    any
+	}{
+		{datasetSemesterFile, &ds.Semester},
+		{datasetDepartmentsFile, &ds.Departments},
+		{datasetCoursesFile, &ds.Courses},
+	} {
+		if err := decode(f.name, f.v); err != nil {
+			return dataset{}, err
+		}
+	}
+	if ds.Semester.Code != ds.Manifest.Semester {
+		return dataset{}, fmt.Errorf("%w: semester %s does not match manifest semester %s", ErrInvalidDataset, ds.Semester.Code, ds.Manifest.Semester)
+	}
+	return ds, nil
+}
+
+// offline reports whether the app serves a dataset rather than scraping.
+func (a *app) offline() bool {
+	return a.config.Dataset != ""
+}
+
+// loadDataset fills the cache from ds and marks the app ready.
+func (a *app) loadDataset(ds dataset) {
+	a.mu.Lock()
+	a.endpoint = fmt.Sprintf("%s/%s", a.config.BaseURL, ds.Manifest.Semester)
+	a.departmentCache = ds.Departments
+	a.mu.Unlock()
+	for _, course := range ds.Courses {
+		a.remember(&CourseParsingResult{Code: course.Code, Course: course})
+	}
+	// The catalogue last changed when the dataset was generated, not when it
+	// was loaded.
+	a.mu.Lock()
+	for code := range a.modified {
+		a.modified[code] = ds.Manifest.GeneratedAt
+	}
+	a.lastModified = ds.Manifest.GeneratedAt
+	a.mu.Unlock()
+	a.markReady(ds.Manifest.GeneratedAt)
+}
+
+func runExport(ctx context.Context, a *app, args []string, stdout io.Writer) error {
+	fs, semesterCode := commandFlags("export")
+	out := fs.String("out", "", "dataset directory, or archive if it ends in .tar.gz or .tgz")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" || fs.NArg() > 0 {
+		return errors.New("export: -out must be given, and no arguments")
+	}
+	if err := a.useSemester(*semesterCode); err != nil {
+		return err
+	}
+	s, err := a.calendar.parseSemester(a.semester())
+	if err != nil {
+		return err
+	}
+	departments, err := a.discoverDepartments(ctx)
+	if err != nil {
+		return err
+	}
+	codes := make([]string, 0, len(departments))
+	for _, d := range departments {
+		codes = append(codes, d.Code)
+	}
+	courses, err := a.scrapeDepartments(ctx, codes)
+	if err != nil {
+		return err
+	}
+	ds := newDataset(s, departments, courses, time.Now().UTC())
+	if err := writeDataset(*out, ds); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "exported %d courses of semester %s to %s\n", ds.Manifest.Courses, s.Code, *out)
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testDataset(t *testing.T) dataset {
+	t.Helper()
+	s, err := defaultCalendar().parseSemester("2510")
+	if err != nil {
+		t.Fatal(err)
+	}
+	courses := []*Course{
+		{
+			Code:     "COMP1021",
+			Title:    "Introduction to Computer Science",
+			Credits:  3,
+			Sections: []string{"L1", "LA1"},
+			Schedule: []Section{{Code: "L1", Quota: 200}, {Code: "LA1", Quota: 40}},
+		},
+		{Code: "COMP2011", Title: "Programming with C++", Credits: 4},
+	}
+	departments := []department{{Code: "COMP", Name: "Computer Science and Engineering", Level: "ug"}}
+	return newDataset(s, departments, courses, time.Date(2025, 9, 1, 2, 0, 0, 0, time.UTC))
+}
+
+func TestDataset_RoundTrip(t *testing.T) {
+	for _, name := range []string{"dataset", "dataset.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), name)
+			want := testDataset(t)
+			if err := writeDataset(out, want); err != nil {
+				t.Fatalf("writeDataset() error: %v", err)
+			}
+			got, err := readDataset(out)
+			if err != nil {
+				t.Fatalf("readDataset() error: %v", err)
+			}
+			if got.Manifest != want.Manifest {
+				t.Errorf("Manifest = %+v, want %+v", got.Manifest, want.Manifest)
+			}
+			if got.Manifest.Sections != 2 || got.Semester != want.Semester {
+				t.Errorf("Semester = %+v, want %+v", got.Semester, want.Semester)
+			}
+			if len(got.Courses) != 2 || len(got.Courses[0].Schedule) != 2 || len(got.Departments) != 1 {
+				t.Errorf("courses = %+v, departments = %+v", got.Courses, got.Departments)
+			}
+		})
+	}
+}
+
+func TestDataset_SectionsFile(t *testing.T) {
+	out := t.TempDir()
+	if err := writeDataset(out, testDataset(t)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filepath.Join(out, datasetSectionsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sections []map[string]any
+	if err := json.Unmarshal(data, &sections); err != nil {
+		t.Fatal(err)
+	}
+	if len(sections) != 2 || sections[1]["course"] != "COMP1021" || sections[1]["code"] != "LA1" {
+		t.Errorf("sections = %v", sections)
+	}
+}
+
+func TestReadDataset_Invalid(t *testing.T) {
+	out := t.TempDir()
+	if err := writeDataset(out, testDataset(t)); err != nil {
+		t.Fatal(err)
+	}
+	manifest := filepath.Join(out, datasetManifestFile)
+	if err := os.WriteFile(manifest, []byte(`{"schema_version": 99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readDataset(out); !errors.Is(err, ErrInvalidDataset) {
+		t.Errorf("readDataset(version 99) error = %v, want ErrInvalidDataset", err)
+	}
+	os.Remove(manifest)
+	if _, err := readDataset(out); !errors.Is(err, ErrInvalidDataset) {
+		t.Errorf("readDataset(no manifest) error = %v, want ErrInvalidDataset", err)
+	}
+}
+
+func offlineApp(t *testing.T) *app {
+	t.Helper()
+	a := testApp()
+	// Any upstream access would fail.
+	a.config.BaseURL = "http://127.0.0.1:1"
+	a.config.Dataset = "dataset"
+	a.loadDataset(testDataset(t))
+	return a
+}
+
+func TestLoadDataset(t *testing.T) {
+	a := offlineApp(t)
+	if a.semester() != "2510" || !a.ready {
+		t.Errorf("semester = %s, ready = %v, want 2510 and ready", a.semester(), a.ready)
+	}
+	if got := a.lastModified; !got.Equal(time.Date(2025, 9, 1, 2, 0, 0, 0, time.UTC)) {
+		t.Errorf("lastModified = %v, want the generation time", got)
+	}
+	if _, ok := a.lookupCourse(t.Context(), "COMP1021", "COMP"); !ok {
+		t.Error("COMP1021 not served from the dataset")
+	}
+	if got := a.suggestCourses("comp20", 10).Suggestions; len(got) != 1 || got[0].Code != "COMP2011" {
+		t.Errorf("suggestions = %+v, want COMP2011", got)
+	}
+}
+
+func TestOffline_ReadOnly(t *testing.T) {
+	a := offlineApp(t)
+
+	c, rec := setupHandlerTest(http.MethodPatch, "/v1/courses", a)
+	if err := a.HandleRefreshCourses(c); err != nil {
+		t.Fatalf("HandleRefreshCourses() error: %v", err)
+	}
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("PATCH status = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+	if a.semester() != "2510" || len(a.cache) != 2 {
+		t.Errorf("PATCH changed the dataset: semester %s with %d courses", a.semester(), len(a.cache))
+	}
+
+	c, rec = setupHandlerTest(http.MethodGet, "/readyz", a)
+	if err := a.HandleReadinessCheck(c); err != nil {
+		t.Fatalf("HandleReadinessCheck() error: %v", err)
+	}
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"upstream":"offline"`) {
+		t.Errorf("readyz = %d %s, want 200 with offline upstream", rec.Code, rec.Body)
+	}
+
+	if codes, err := a.availableSemesters(t.Context()); err != nil || len(codes) != 1 || codes[0] != "2510" {
+		t.Errorf("availableSemesters() = %v, %v, want [2510]", codes, err)
+	}
+}
+
+func TestRunCommand_Export(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "catalogue.tgz")
+	if _, err := runTestCommand(t, "export", "-semester", "2510", "-out", out); err != nil {
+		t.Fatalf("export error: %v", err)
+	}
+	ds, err := readDataset(out)
+	if err != nil {
+		t.Fatalf("readDataset() error: %v", err)
+	}
+	if ds.Manifest.Semester != "2510" || ds.Manifest.Courses != 1 || ds.Manifest.Departments != 1 || ds.Manifest.Sections != 2 {
+		t.Errorf("Manifest = %+v", ds.Manifest)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// departmentShrinkThreshold is the fraction of the previously discovered
+// department list that may disappear between crawls before discovery is
+// treated as a failure rather than a genuine catalogue change.
+const departmentShrinkThreshold = 0.5
+
+type department struct {
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Level string `json:"level"`
+}
+
+func parseDepartment(e *colly.HTMLElement) department {
+	level := "ug"
+	if e.DOM.HasClass("pg") {
+		level = "pg"
+	}
+	return department{
+		Code:  strings.TrimSpace(e.Text),
+		Name:  strings.TrimSpace(e.Attr("title")),
+		Level: level,
+	}
+}
+
+// discoverDepartments scrapes the department list from the index page of the
+// current semester and checks it against the list found by the previous
+// crawl.
+func (a *app) discoverDepartments(ctx context.Context) ([]department, error) {
+	a.mu.RLock()
+	endpoint, previous := a.endpoint, len(a.departmentCache)
+	a.mu.RUnlock()
+	return a.discoverDepartmentsAt(ctx, endpoint, previous)
+}
+
+// discoverDepartmentsAt scrapes the department list from the semester index
+// page at endpoint. Unless previous is zero, discovery fails if the list is
+// much shorter than the previous number of departments.
+func (a *app) discoverDepartmentsAt(ctx context.Context, endpoint string, previous int) ([]department, error) {
+	var departments []department
+	seen := make(map[string]bool)
+	collector := colly.NewCollector(colly.StdlibContext(ctx))
+	collector.WithTransport(tracingTransport{base: http.DefaultTransport})
+	collector.OnHTML("a.ug, a.pg", func(e *colly.HTMLElement) {
+		d := parseDepartment(e)
+		if d.Code == "" || seen[d.Code] {
+			return
+		}
+		seen[d.Code] = true
+		departments = append(departments, d)
+	})
+	if err := collector.Visit(fmt.Sprintf("%s/", endpoint)); err != nil {
+		return nil, fmt.Errorf("department discovery: %w", err)
+	}
+	if len(departments) == 0 {
+		departmentDiscoveryFailures.Inc()
+		return nil, ErrNoDepartments
+	}
+
+	if previous > 0 && float64(len(departments)) < float64(previous)*(1-departmentShrinkThreshold) {
+		departmentDiscoveryFailures.Inc()
+		a.logger.Error("department list shrank sharply since previous crawl",
+			slog.Int("previous", previous),
+			slog.Int("current", len(departments)))
+		return nil, fmt.Errorf("%w: %d departments found, previously %d", ErrDepartmentListShrunk, len(departments), previous)
+	}
+
+	departmentsDiscovered.Set(float64(len(departments)))
+	return departments, nil
+}
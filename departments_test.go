@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// departmentIndex renders a semester index page listing the given department
+// codes as undergraduate links.
+func departmentIndex(codes ...string) string {
+	var b strings.Builder
+	b.WriteString(`<html><body><div class="depts">`)
+	for _, code := range codes {
+		fmt.Fprintf(&b, `<a href="subject/%s" class="ug" title="%s Department">%s</a>`, code, code, code)
+	}
+	b.WriteString(`</div></body></html>`)
+	return b.String()
+}
+
+func TestDiscoverDepartments(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><div class="depts">
+			<a href="subject/ACCT" class="ug" title="Accounting">ACCT</a>
+			<a href="subject/COMP" class="ug" title="Computer Science and Engineering">COMP</a>
+			<a href="subject/CSIT" class="pg" title="Computer Science and Information Technology">CSIT</a>
+			<a href="subject/COMP" class="ug" title="Computer Science and Engineering">COMP</a>
+		</div></body></html>`)
+	}))
+	defer srv.Close()
+
+	a := testApp()
+	a.endpoint = srv.URL
+	departments, err := a.discoverDepartments(context.Background())
+	if err != nil {
+		t.Fatalf("discoverDepartments() error: %v", err)
+	}
+	want := []department{
+		{Code: "ACCT", Name: "Accounting", Level: "ug"},
+		{Code: "COMP", Name: "Computer Science and Engineering", Level: "ug"},
+		{Code: "CSIT", Name: "Computer Science and Information Technology", Level: "pg"},
+	}
+	if len(departments) != len(want) {
+		t.Fatalf("len(departments) = %d, want %d", len(departments), len(want))
+	}
+	for i := range want {
+		if departments[i] != want[i] {
+			t.Errorf("departments[%d] = %+v, want %+v", i, departments[i], want[i])
+		}
+	}
+}
+
+func TestDiscoverDepartments_Empty(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, departmentIndex())
+	}))
+	defer srv.Close()
+
+	a := testApp()
+	a.endpoint = srv.URL
+	if _, err := a.discoverDepartments(context.Background()); !errors.Is(err, ErrNoDepartments) {
+		t.Errorf("error = %v, want ErrNoDepartments", err)
+	}
+}
+
+func TestDiscoverDepartments_Shrunk(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, departmentIndex("COMP"))
+	}))
+	defer srv.Close()
+
+	a := testApp()
+	a.endpoint = srv.URL
+	a.departmentCache = []department{{Code: "ACCT"}, {Code: "COMP"}, {Code: "MATH"}, {Code: "PHYS"}}
+	if _, err := a.discoverDepartments(context.Background()); !errors.Is(err, ErrDepartmentListShrunk) {
+		t.Errorf("error = %v, want ErrDepartmentListShrunk", err)
+	}
+}
+
+func TestPreCacheCurrentSemesterCourses_KeepsDepartmentsOnFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, departmentIndex())
+	}))
+	defer srv.Close()
+
+	a := testApp()
+	a.endpoint = srv.URL
+	previous := []department{{Code: "COMP"}}
+	a.departmentCache = previous
+	if err := a.PreCacheCurrentSemesterCourses(context.Background()); err == nil {
+		t.Fatal("PreCacheCurrentSemesterCourses() expected error, got nil")
+	}
+	if len(a.departmentCache) != 1 || a.departmentCache[0].Code != "COMP" {
+		t.Errorf("departmentCache = %+v, want %+v", a.departmentCache, previous)
+	}
+}
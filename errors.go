@@ -3,3 +3,41 @@ package main
 import "errors"
 
 var ErrInvalidSemesterCode = errors.New("invalid semester code")
+
+var ErrNoDepartments = errors.New("no departments found on semester index")
+
+var ErrDepartmentListShrunk = errors.New("department list shrank sharply since previous crawl")
+
+var ErrIncompleteCrawl = errors.New("not every department could be crawled")
+
+var ErrSemesterChanged = errors.New("semester served changed during the crawl")
+
+var ErrInvalidCourseCode = errors.New("course code must have an alphabetic department prefix followed by a number")
+
+var ErrUnsupportedFormat = errors.New("unsupported output format")
+
+var ErrUnknownField = errors.New("unknown course field")
+
+var ErrFieldsWithCSV = errors.New("fields cannot be selected for CSV output")
+
+var ErrUnsupportedConfigFormat = errors.New("config file must have a .yaml, .yml or .toml extension")
+
+var ErrUnknownConfigKey = errors.New("unknown config key")
+
+var ErrSemesterNotAvailable = errors.New("semester is not available")
+
+var ErrInvalidCalendar = errors.New("invalid academic calendar")
+
+var ErrEmptySearchQuery = errors.New("search query q must contain at least one word")
+
+var ErrSearchQueryTooLong = errors.New("search query q is too long")
+
+var ErrInvalidLimit = errors.New("invalid limit")
+
+var ErrEmptyPrefix = errors.New("prefix must not be empty")
+
+var ErrInvalidRequirements = errors.New("invalid requirement definition")
+
+var ErrInvalidDataset = errors.New("invalid dataset")
+
+var ErrReadOnlyDataset = errors.New("serving a read-only dataset")
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	formatJSON   = "json"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+
+	mimeTextCSV = "text/csv"
+	mimeNDJSON  = "application/x-ndjson"
+)
+
+// utf8BOM prefixes CSV output so that Excel detects the encoding of
+// non-ASCII instructor names.
+const utf8BOM = "\xef\xbb\xbf"
+
+var csvHeader = []string{
+	"course_code", "course_title", "credits", "section",
+	"time", "room", "instructors", "quota", "enrol", "avail", "wait",
+}
+
+// courseFields lists the JSON fields of a Course that can be selected with
+// the fields query parameter.
+var courseFields = []string{"code", "title", "credits", "instructors", "sections", "schedule", "co_list_with", "description", "prerequisites", "exclusions"}
+
+// parseFields reads the fields query parameter, returning nil when every
+// field should be included.
+func parseFields(c echo.Context) ([]string, error) {
+	param := c.QueryParam("fields")
+	if param == "" {
+		return nil, nil
+	}
+	var fields []string
+	for f := range strings.SplitSeq(param, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if !slices.Contains(courseFields, f) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownField, f)
+		}
+		if !slices.Contains(fields, f) {
+			fields = append(fields, f)
+		}
+	}
+	slices.Sort(fields)
+	return fields, nil
+}
+
+// selectFields returns course restricted to fields, or course itself when
+// fields is nil.
+func selectFields(course *Course, fields []string) any {
+	if fields == nil {
+		return course
+	}
+	// Marshalling a Course cannot fail; see writeCourseDigest.
+	b, _ := json.Marshal(course)
+	var all map[string]json.RawMessage
+	json.Unmarshal(b, &all)
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		if v, ok := all[f]; ok {
+			selected[f] = v
+		}
+	}
+	return selected
+}
+
+// representation identifies an output format and field selection, keeping
+// entity tags of different representations distinct.
+func representation(format string, fields []string) string {
+	if fields == nil {
+		return format
+	}
+	return format + ";fields=" + strings.Join(fields, ",")
+}
+
+// negotiateFormat picks the list output format from the format query
+// parameter, falling back to the Accept header and then to JSON.
+func negotiateFormat(c echo.Context) (string, error) {
+	if f := strings.ToLower(c.QueryParam("format")); f != "" {
+		switch f {
+		case formatJSON, formatCSV, formatNDJSON:
+			return f, nil
+		}
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, f)
+	}
+	for accept := range strings.SplitSeq(c.Request().Header.Get(echo.HeaderAccept), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case mimeTextCSV:
+			return formatCSV, nil
+		case mimeNDJSON:
+			return formatNDJSON, nil
+		case echo.MIMEApplicationJSON:
+			return formatJSON, nil
+		}
+	}
+	return formatJSON, nil
+}
+
+// writeCourses streams courses to the response in the given format,
+// restricted to fields for JSON and NDJSON output.
+func writeCourses(c echo.Context, format string, fields []string, courses []*Course) error {
+	switch format {
+	case formatCSV:
+		return writeCoursesCSV(c, courses)
+	case formatNDJSON:
+		return writeCoursesNDJSON(c, fields, courses)
+	}
+	if fields == nil {
+		return c.JSON(http.StatusOK, courses)
+	}
+	selected := make([]any, 0, len(courses))
+	for _, course := range courses {
+		selected = append(selected, selectFields(course, fields))
+	}
+	return c.JSON(http.StatusOK, selected)
+}
+
+func writeCoursesNDJSON(c echo.Context, fields []string, courses []*Course) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, mimeNDJSON)
+	res.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(res)
+	for _, course := range courses {
+		if err := enc.Encode(selectFields(course, fields)); err != nil {
+			return err
+		}
+		res.Flush()
+	}
+	return nil
+}
+
+func writeCoursesCSV(c echo.Context, courses []*Course) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, mimeTextCSV+"; charset=utf-8")
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="courses.csv"`)
+	res.WriteHeader(http.StatusOK)
+	if _, err := res.Write([]byte(utf8BOM)); err != nil {
+		return err
+	}
+	w := csv.NewWriter(res)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, course := range courses {
+		for _, row := range sectionRows(course) {
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+		res.Flush()
+	}
+	return nil
+}
+
+// sectionRows flattens a course into one CSV row per section. Courses
+// without sections produce a single row with the section columns empty.
+func sectionRows(course *Course) [][]string {
+	credits := strconv.FormatFloat(course.Credits, 'f', -1, 64)
+	if len(course.Sections) == 0 {
+		return [][]string{{course.Code, course.Title, credits, "", "", "", "", "", "", "", ""}}
+	}
+	rows := make([][]string, 0, len(course.Sections))
+	for _, code := range course.Sections {
+		var instructors []string
+		for name, sections := range course.Instructors {
+			if slices.Contains(sections, code) {
+				instructors = append(instructors, name)
+			}
+		}
+		slices.Sort(instructors)
+		row := []string{course.Code, course.Title, credits, code, "", "", strings.Join(instructors, "; "), "", "", "", ""}
+		if i := slices.IndexFunc(course.Schedule, func(s Section) bool { return s.Code == code }); i >= 0 {
+			s := course.Schedule[i]
+			row[4] = s.Time
+			row[5] = s.Room
+			row[7] = strconv.Itoa(s.Quota)
+			row[8] = strconv.Itoa(s.Enrol)
+			row[9] = strconv.Itoa(s.Avail)
+			row[10] = strconv.Itoa(s.Wait)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
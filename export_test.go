@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestHandleGetCourses_CSV(t *testing.T) {
+	a := fixtureApp()
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/courses?format=csv", a)
+
+	if err := a.HandleGetCourses(c); err != nil {
+		t.Fatalf("HandleGetCourses() error: %v", err)
+	}
+	if got := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(got, mimeTextCSV) {
+		t.Errorf("Content-Type = %q, want %s", got, mimeTextCSV)
+	}
+	body, ok := strings.CutPrefix(rec.Body.String(), utf8BOM)
+	if !ok {
+		t.Error("CSV output does not start with a UTF-8 byte order mark")
+	}
+	rows, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	want := [][]string{
+		csvHeader,
+		{"COMP1021", "Introduction to Computer Science", "3", "L1", "TuTh 03:00PM - 04:20PM", "LTA", "LAM, Gibson", "200", "180", "20", "0"},
+		{"COMP1021", "Introduction to Computer Science", "3", "LA1", "Mo 09:00AM - 10:50AM", "Rm 4210", "TA, Alice; TA, Bob", "40", "40", "0", "3"},
+		{"COMP1022P", "Introduction to Python Programming", "3", "L1", "TuTh 03:00PM - 04:20PM", "LTB", "LAM, Gibson", "150", "150", "0", "0"},
+		{"MATH1013", "Calculus IB", "3", "", "", "", "", "", "", "", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("len(rows) = %d, want %d", len(rows), len(want))
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("rows[%d] = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestHandleGetCourses_NDJSON(t *testing.T) {
+	a := fixtureApp()
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/courses", a)
+	c.Request().Header.Set(echo.HeaderAccept, "application/x-ndjson")
+
+	if err := a.HandleGetCourses(c); err != nil {
+		t.Fatalf("HandleGetCourses() error: %v", err)
+	}
+	if got := rec.Header().Get(echo.HeaderContentType); got != mimeNDJSON {
+		t.Errorf("Content-Type = %q, want %s", got, mimeNDJSON)
+	}
+	var codes []string
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var course Course
+		if err := json.Unmarshal(scanner.Bytes(), &course); err != nil {
+			t.Fatalf("failed to unmarshal line %q: %v", scanner.Text(), err)
+		}
+		codes = append(codes, course.Code)
+	}
+	if strings.Join(codes, ",") != "COMP1021,COMP1022P,MATH1013" {
+		t.Errorf("codes = %v, want [COMP1021 COMP1022P MATH1013]", codes)
+	}
+}
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		accept  string
+		want    string
+		wantErr bool
+	}{
+		{"default", "/v1/courses", "", formatJSON, false},
+		{"accept csv", "/v1/courses", "text/csv", formatCSV, false},
+		{"accept list", "/v1/courses", "text/html, application/x-ndjson;q=0.9", formatNDJSON, false},
+		{"query overrides accept", "/v1/courses?format=json", "text/csv", formatJSON, false},
+		{"query case insensitive", "/v1/courses?format=CSV", "", formatCSV, false},
+		{"unsupported", "/v1/courses?format=xml", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := setupHandlerTest(http.MethodGet, tt.target, testApp())
+			c.Request().Header.Set(echo.HeaderAccept, tt.accept)
+			got, err := negotiateFormat(c)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("negotiateFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("negotiateFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleGetCourses_Fields(t *testing.T) {
+	a := fixtureApp()
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/courses?fields=code,title,credits", a)
+
+	if err := a.HandleGetCourses(c); err != nil {
+		t.Fatalf("HandleGetCourses() error: %v", err)
+	}
+	want := `[{"code":"COMP1021","credits":3,"title":"Introduction to Computer Science"},{"code":"COMP1022P","credits":3,"title":"Introduction to Python Programming"},{"code":"MATH1013","credits":3,"title":"Calculus IB"}]`
+	if got := bytes.TrimSpace(rec.Body.Bytes()); string(got) != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
+
+func TestHandleGetCourses_FieldsInvalid(t *testing.T) {
+	for _, target := range []string{"/v1/courses?fields=code,grading", "/v1/courses?format=csv&fields=code"} {
+		a := fixtureApp()
+		c, rec := setupHandlerTest(http.MethodGet, target, a)
+		if err := a.HandleGetCourses(c); err != nil {
+			t.Fatalf("HandleGetCourses() error: %v", err)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", target, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHandleGetCourse_Fields(t *testing.T) {
+	a := fixtureApp()
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/courses/COMP1021?fields=code", a)
+	c.SetParamNames("course")
+	c.SetParamValues("COMP1021")
+
+	if err := a.HandleGetCourse(c); err != nil {
+		t.Fatalf("HandleGetCourse() error: %v", err)
+	}
+	if got := bytes.TrimSpace(rec.Body.Bytes()); string(got) != `{"code":"COMP1021"}` {
+		t.Errorf("body = %s, want %s", got, `{"code":"COMP1021"}`)
+	}
+}
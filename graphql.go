@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed schema.graphql
+var graphQLSchema string
+
+// maxGraphQLScrapes is the number of departments a single GraphQL request may
+// have scraped for courses missing from the cache. Further lookups are served
+// from the cache only, so that one query cannot crawl the whole upstream.
+const maxGraphQLScrapes = 2
+
+// maxGraphQLDepth is the deepest field nesting a query may have. It admits
+// courses { sections { instructors { name } } }, but not a second trip round
+// the Course.department and Department.courses cycle, whose responses grow
+// multiplicatively.
+const maxGraphQLDepth = 4
+
+// maxGraphQLParallelism is the number of resolvers of one request that may
+// run at once.
+const maxGraphQLParallelism = 4
+
+func (a *app) graphQLHandler() echo.HandlerFunc {
+	schema := graphql.MustParseSchema(graphQLSchema, &queryResolver{app: a},
+		graphql.UseFieldResolvers(),
+		graphql.MaxDepth(maxGraphQLDepth),
+		graphql.MaxParallelism(maxGraphQLParallelism),
+	)
+	h := &relay.Handler{Schema: schema}
+	return func(c echo.Context) error {
+		ctx := context.WithValue(c.Request().Context(), scrapeBudgetKey{}, &scrapeBudget{})
+		h.ServeHTTP(c.Response(), c.Request().WithContext(ctx))
+		return nil
+	}
+}
+
+type scrapeBudgetKey struct{}
+
+// scrapeBudget tracks the departments scraped on behalf of one request.
+type scrapeBudget struct {
+	mu      sync.Mutex
+	scraped []string
+}
+
+// allow reports whether department may be scraped, recording it if so. Each
+// department is scraped at most once, and at most maxGraphQLScrapes in all.
+func (b *scrapeBudget) allow(department string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if slices.Contains(b.scraped, department) || len(b.scraped) >= maxGraphQLScrapes {
+		return false
+	}
+	b.scraped = append(b.scraped, department)
+	return true
+}
+
+type queryResolver struct {
+	app *app
+}
+
+func (q *queryResolver) Semester(args struct{ Code string }) (*semester, error) {
+	code, err := q.app.resolveSemesterCode(args.Code)
+	if err != nil {
+		return nil, err
+	}
+	s, err := q.app.calendar.parseSemester(code)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (q *queryResolver) Departments() []*departmentResolver {
+	q.app.mu.RLock()
+	departments := slices.Clone(q.app.departmentCache)
+	q.app.mu.RUnlock()
+	resolvers := make([]*departmentResolver, 0, len(departments))
+	for _, d := range departments {
+		resolvers = append(resolvers, &departmentResolver{app: q.app, department: d})
+	}
+	return resolvers
+}
+
+func (q *queryResolver) Department(args struct{ Code string }) *departmentResolver {
+	d, ok := q.app.findDepartment(strings.ToUpper(args.Code))
+	if !ok {
+		return nil
+	}
+	return &departmentResolver{app: q.app, department: d}
+}
+
+func (q *queryResolver) Course(ctx context.Context, args struct{ Code string }) (*courseResolver, error) {
+	code, department, err := normalizeCourseCode(args.Code)
+	if err != nil {
+		return nil, err
+	}
+	q.app.mu.RLock()
+	course, ok := q.app.cachedCourseLocked(code)
+	q.app.mu.RUnlock()
+	if !ok {
+		if budget, _ := ctx.Value(scrapeBudgetKey{}).(*scrapeBudget); budget == nil || !budget.allow(department) {
+			return nil, nil
+		}
+		if course, ok = q.app.lookupCourse(ctx, code, department); !ok {
+			return nil, nil
+		}
+	}
+	return &courseResolver{app: q.app, course: course}, nil
+}
+
+func (q *queryResolver) Courses(args struct{ Department *string }) ([]*courseResolver, error) {
+	if args.Department == nil {
+		return q.app.courseResolvers(q.app.cachedCourses("")), nil
+	}
+	department := strings.ToUpper(*args.Department)
+	if department == "" {
+		return nil, errors.New("department must not be empty")
+	}
+	return q.app.courseResolvers(q.app.cachedCourses(department)), nil
+}
+
+type departmentResolver struct {
+	app        *app
+	department department
+}
+
+func (d *departmentResolver) Code() string  { return d.department.Code }
+func (d *departmentResolver) Name() string  { return d.department.Name }
+func (d *departmentResolver) Level() string { return d.department.Level }
+
+func (d *departmentResolver) Courses() []*courseResolver {
+	return d.app.courseResolvers(d.app.cachedCourses(d.department.Code))
+}
+
+type courseResolver struct {
+	app    *app
+	course *Course
+}
+
+func (c *courseResolver) Code() string     { return c.course.Code }
+func (c *courseResolver) Title() string    { return c.course.Title }
+func (c *courseResolver) Credits() float64 { return c.course.Credits }
+
+func (c *courseResolver) Department() *departmentResolver {
+	code := extractDepartment(c.course.Code)
+	d, ok := c.app.findDepartment(code)
+	if !ok {
+		d = department{Code: code}
+	}
+	return &departmentResolver{app: c.app, department: d}
+}
+
+func (c *courseResolver) Sections() []*sectionResolver {
+	resolvers := make([]*sectionResolver, 0, len(c.course.Sections))
+	for _, code := range c.course.Sections {
+		resolvers = append(resolvers, &sectionResolver{course: c.course, code: code})
+	}
+	return resolvers
+}
+
+func (c *courseResolver) Instructors() []*instructorResolver {
+	return instructorResolvers(c.course, func(string) bool { return true })
+}
+
+type sectionResolver struct {
+	course *Course
+	code   string
+}
+
+func (s *sectionResolver) Code() string { return s.code }
+
+func (s *sectionResolver) Instructors() []*instructorResolver {
+	return instructorResolvers(s.course, func(section string) bool { return section == s.code })
+}
+
+type instructorResolver struct {
+	name     string
+	sections []string
+}
+
+func (i *instructorResolver) Name() string       { return i.name }
+func (i *instructorResolver) Sections() []string { return i.sections }
+
+// instructorResolvers returns the instructors of course teaching at least one
+// section accepted by match, sorted by name.
+func instructorResolvers(course *Course, match func(section string) bool) []*instructorResolver {
+	var resolvers []*instructorResolver
+	for name, sections := range course.Instructors {
+		if slices.ContainsFunc(sections, match) {
+			resolvers = append(resolvers, &instructorResolver{name: name, sections: sections})
+		}
+	}
+	slices.SortFunc(resolvers, func(x, y *instructorResolver) int {
+		return strings.Compare(x.name, y.name)
+	})
+	return resolvers
+}
+
+func (a *app) courseResolvers(courses []*Course) []*courseResolver {
+	resolvers := make([]*courseResolver, 0, len(courses))
+	for _, course := range courses {
+		resolvers = append(resolvers, &courseResolver{app: a, course: course})
+	}
+	return resolvers
+}
+
+// findDepartment returns the discovered department with the given code.
+func (a *app) findDepartment(code string) (department, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	i := slices.IndexFunc(a.departmentCache, func(d department) bool { return d.Code == code })
+	if i < 0 {
+		return department{}, false
+	}
+	return a.departmentCache[i], true
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// graphQLQuery posts query to the GraphQL handler of a and decodes the data
+// field of the response into out.
+func graphQLQuery(t *testing.T, a *app, query string, out any) {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		t.Fatalf("failed to marshal request: %v", err)
+	}
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(string(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	if err := a.graphQLHandler()(e.NewContext(req, rec)); err != nil {
+		t.Fatalf("graphQLHandler() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Errors) > 0 {
+		t.Fatalf("query returned errors: %+v", resp.Errors)
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		t.Fatalf("failed to unmarshal data: %v", err)
+	}
+}
+
+func TestGraphQL_Course(t *testing.T) {
+	a := testApp()
+	a.departmentCache = []department{{Code: "COMP", Name: "Computer Science and Engineering", Level: "ug"}}
+	a.cache["COMP1021"] = &Course{
+		Code:     "COMP1021",
+		Title:    "Introduction to Computer Science",
+		Credits:  3,
+		Sections: []string{"L1", "LA1"},
+		Instructors: map[string][]string{
+			"LAM, Gibson": {"L1"},
+			"TA, Alice":   {"LA1"},
+		},
+	}
+	a.cache["COMP2011"] = &Course{Code: "COMP2011", Title: "Programming with C++", Credits: 4}
+
+	var data struct {
+		Course struct {
+			Title      string
+			Department struct {
+				Name    string
+				Courses []struct{ Code string }
+			}
+			Sections []struct {
+				Code        string
+				Instructors []struct{ Name string }
+			}
+		}
+	}
+	graphQLQuery(t, a, `{
+		course(code: "comp1021") {
+			title
+			department { name courses { code } }
+			sections { code instructors { name } }
+		}
+	}`, &data)
+
+	if data.Course.Title != "Introduction to Computer Science" {
+		t.Errorf("title = %q, want %q", data.Course.Title, "Introduction to Computer Science")
+	}
+	if data.Course.Department.Name != "Computer Science and Engineering" {
+		t.Errorf("department.name = %q, want full name", data.Course.Department.Name)
+	}
+	if len(data.Course.Department.Courses) != 2 {
+		t.Errorf("len(department.courses) = %d, want 2", len(data.Course.Department.Courses))
+	}
+	if len(data.Course.Sections) != 2 {
+		t.Fatalf("len(sections) = %d, want 2", len(data.Course.Sections))
+	}
+	lab := data.Course.Sections[1]
+	if lab.Code != "LA1" || len(lab.Instructors) != 1 || lab.Instructors[0].Name != "TA, Alice" {
+		t.Errorf("sections[1] = %+v, want LA1 taught by TA, Alice", lab)
+	}
+}
+
+func TestGraphQL_CourseNotFound(t *testing.T) {
+	a := testApp()
+	a.endpoint = "http://127.0.0.1:1/invalid"
+
+	var data struct {
+		Course *struct{ Code string }
+	}
+	graphQLQuery(t, a, `{ course(code: "COMP9999") { code } }`, &data)
+	if data.Course != nil {
+		t.Errorf("course = %+v, want null", data.Course)
+	}
+}
+
+func TestGraphQL_ScrapesLimitedPerRequest(t *testing.T) {
+	var mu sync.Mutex
+	var scraped []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		scraped = append(scraped, path.Base(r.URL.Path))
+		mu.Unlock()
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+	a := testApp()
+	a.endpoint = srv.URL + "/2510"
+	a.departmentCache = []department{{Code: "COMP"}, {Code: "MATH"}, {Code: "PHYS"}}
+
+	var departments struct {
+		Departments []struct{ Courses []struct{ Code string } }
+	}
+	graphQLQuery(t, a, `{ departments { courses { code } } }`, &departments)
+	if len(scraped) != 0 {
+		t.Errorf("departments query scraped %v, want it served from the cache", scraped)
+	}
+
+	var courses map[string]*struct{ Code string }
+	graphQLQuery(t, a, `{
+		a: course(code: "COMP1021") { code }
+		b: course(code: "COMP2011") { code }
+		c: course(code: "MATH1013") { code }
+		d: course(code: "PHYS1112") { code }
+	}`, &courses)
+	// The two COMP lookups share one scrape, and the budget covers two departments.
+	slices.Sort(scraped)
+	if len(scraped) != maxGraphQLScrapes || len(slices.Compact(slices.Clone(scraped))) != maxGraphQLScrapes {
+		t.Errorf("scraped %v, want %d distinct departments", scraped, maxGraphQLScrapes)
+	}
+}
+
+func TestGraphQL_MaxDepth(t *testing.T) {
+	a := testApp()
+	a.departmentCache = []department{{Code: "COMP"}}
+	a.cache["COMP1021"] = &Course{Code: "COMP1021"}
+
+	var data struct{ Courses []struct{ Code string } }
+	graphQLQuery(t, a, `{ courses { sections { instructors { name } } code } }`, &data)
+	if len(data.Courses) != 1 {
+		t.Errorf("courses = %+v, want COMP1021", data.Courses)
+	}
+
+	body := `{"query": "{ departments { courses { department { courses { code } } } } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	if err := a.graphQLHandler()(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatalf("graphQLHandler() error: %v", err)
+	}
+	if !strings.Contains(rec.Body.String(), "exceeds max depth") || strings.Contains(rec.Body.String(), `"data"`) {
+		t.Errorf("too deep query answered with %s, want a max depth error", rec.Body)
+	}
+}
+
+func TestGraphQL_Semester(t *testing.T) {
+	a := testApp()
+
+	var data struct {
+		Semester struct{ Code, Name string }
+	}
+	graphQLQuery(t, a, `{ semester(code: "2510") { code name } }`, &data)
+	if data.Semester.Code != "2510" || !strings.Contains(data.Semester.Name, "Fall") {
+		t.Errorf("semester = %+v, want 2510 Fall", data.Semester)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/hkust-cse/crapi/courseinfopb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// newGRPCServer returns a gRPC server exposing the course catalogue and the
+// standard health service. Both report NOT_SERVING until the app is ready.
+func (a *app) newGRPCServer() *grpc.Server {
+	s := grpc.NewServer()
+	courseinfopb.RegisterCourseInfoServer(s, &grpcServer{app: a})
+	healthpb.RegisterHealthServer(s, a.health)
+	a.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	a.health.SetServingStatus(courseinfopb.CourseInfo_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	return s
+}
+
+// setServing marks the gRPC health service as serving.
+func (a *app) setServing() {
+	if a.health == nil {
+		return
+	}
+	a.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	a.health.SetServingStatus(courseinfopb.CourseInfo_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+}
+
+// stopGRPCServer drains in-flight RPCs, forcibly closing any that are still
+// running (such as WatchChanges streams) when ctx expires.
+func (a *app) stopGRPCServer(ctx context.Context) {
+	if a.health != nil {
+		a.health.Shutdown()
+	}
+	stopped := make(chan struct{})
+	go func() {
+		a.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		a.grpcServer.Stop()
+	}
+}
+
+type grpcServer struct {
+	courseinfopb.UnimplementedCourseInfoServer
+	app *app
+}
+
+func (s *grpcServer) GetCourse(ctx context.Context, req *courseinfopb.GetCourseRequest) (*courseinfopb.Course, error) {
+	s.app.logger.Info("gRPC GetCourse", "course", req.GetCode())
+	code, department, err := normalizeCourseCode(req.GetCode())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	course, ok := s.app.lookupCourse(ctx, code, department)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "course %s not found", code)
+	}
+	return courseToProto(course), nil
+}
+
+func (s *grpcServer) ListCourses(req *courseinfopb.ListCoursesRequest, stream grpc.ServerStreamingServer[courseinfopb.Course]) error {
+	s.app.logger.Info("gRPC ListCourses", "department", req.GetDepartment())
+	for _, course := range s.app.cachedCourses(strings.ToUpper(req.GetDepartment())) {
+		if err := stream.Send(courseToProto(course)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *grpcServer) GetSemester(ctx context.Context, req *courseinfopb.GetSemesterRequest) (*courseinfopb.Semester, error) {
+	s.app.logger.Info("gRPC GetSemester", "semester", req.GetCode())
+	code := req.GetCode()
+	if code == "" {
+		code = "current"
+	}
+	code, err := s.app.resolveSemesterCode(code)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	sem, err := s.app.calendar.parseSemester(code)
+	if err != nil {
+		if errors.Is(err, ErrInvalidSemesterCode) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return &courseinfopb.Semester{
+		Code:   sem.Code,
+		Name:   sem.Name,
+		Year:   sem.Year,
+		Cohort: sem.Cohort,
+		Start:  sem.Start,
+		End:    sem.End,
+	}, nil
+}
+
+func (s *grpcServer) WatchChanges(req *courseinfopb.WatchChangesRequest, stream grpc.ServerStreamingServer[courseinfopb.CourseChange]) error {
+	s.app.logger.Info("gRPC WatchChanges", "department", req.GetDepartment())
+	department := strings.ToUpper(req.GetDepartment())
+	changes, stop := s.app.watch()
+	defer stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case change := <-changes:
+			if department != "" && extractDepartment(change.Course.Code) != department {
+				continue
+			}
+			err := stream.Send(&courseinfopb.CourseChange{
+				Type:   change.Type,
+				Course: courseToProto(change.Course),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func courseToProto(c *Course) *courseinfopb.Course {
+	names := slices.Sorted(maps.Keys(c.Instructors))
+	instructors := make([]*courseinfopb.Instructor, 0, len(names))
+	for _, name := range names {
+		instructors = append(instructors, &courseinfopb.Instructor{
+			Name:     name,
+			Sections: c.Instructors[name],
+		})
+	}
+	return &courseinfopb.Course{
+		Code:        c.Code,
+		Title:       c.Title,
+		Credits:     c.Credits,
+		Instructors: instructors,
+		Sections:    c.Sections,
+	}
+}
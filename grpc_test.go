@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hkust-cse/crapi/courseinfopb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// grpcTestClient serves a's gRPC server over an in-memory listener and
+// returns a connection to it.
+func grpcTestClient(t *testing.T, a *app) *grpc.ClientConn {
+	t.Helper()
+	a.health = health.NewServer()
+	a.grpcServer = a.newGRPCServer()
+	lis := bufconn.Listen(1 << 20)
+	go a.grpcServer.Serve(lis)
+	t.Cleanup(a.grpcServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestGRPC_GetCourse(t *testing.T) {
+	a := testApp()
+	a.endpoint = "http://127.0.0.1:1/invalid"
+	a.cache["COMP1021"] = &Course{
+		Code:        "COMP1021",
+		Title:       "Introduction to Computer Science",
+		Credits:     3,
+		Instructors: map[string][]string{"LAM, Gibson": {"L1"}},
+		Sections:    []string{"L1"},
+	}
+	client := courseinfopb.NewCourseInfoClient(grpcTestClient(t, a))
+
+	course, err := client.GetCourse(t.Context(), &courseinfopb.GetCourseRequest{Code: "comp1021"})
+	if err != nil {
+		t.Fatalf("GetCourse() error: %v", err)
+	}
+	if course.GetTitle() != "Introduction to Computer Science" {
+		t.Errorf("title = %q, want %q", course.GetTitle(), "Introduction to Computer Science")
+	}
+	if len(course.GetInstructors()) != 1 || course.GetInstructors()[0].GetName() != "LAM, Gibson" {
+		t.Errorf("instructors = %v, want LAM, Gibson", course.GetInstructors())
+	}
+
+	tests := []struct {
+		code string
+		want codes.Code
+	}{
+		{"COMP9999", codes.NotFound},
+		{"1234", codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		_, err := client.GetCourse(t.Context(), &courseinfopb.GetCourseRequest{Code: tt.code})
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("GetCourse(%s) code = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestGRPC_ListCourses(t *testing.T) {
+	a := testApp()
+	a.cache["COMP1021"] = &Course{Code: "COMP1021"}
+	a.cache["COMP2011"] = &Course{Code: "COMP2011"}
+	a.cache["MATH1013"] = &Course{Code: "MATH1013"}
+	client := courseinfopb.NewCourseInfoClient(grpcTestClient(t, a))
+
+	stream, err := client.ListCourses(t.Context(), &courseinfopb.ListCoursesRequest{Department: "comp"})
+	if err != nil {
+		t.Fatalf("ListCourses() error: %v", err)
+	}
+	var got []string
+	for {
+		course, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error: %v", err)
+		}
+		got = append(got, course.GetCode())
+	}
+	if len(got) != 2 || got[0] != "COMP1021" || got[1] != "COMP2011" {
+		t.Errorf("courses = %v, want [COMP1021 COMP2011]", got)
+	}
+}
+
+func TestGRPC_GetSemester(t *testing.T) {
+	a := testApp()
+	client := courseinfopb.NewCourseInfoClient(grpcTestClient(t, a))
+
+	s, err := client.GetSemester(t.Context(), &courseinfopb.GetSemesterRequest{Code: "2510"})
+	if err != nil {
+		t.Fatalf("GetSemester() error: %v", err)
+	}
+	if s.GetYear() != "2025" {
+		t.Errorf("year = %q, want %q", s.GetYear(), "2025")
+	}
+	if _, err := client.GetSemester(t.Context(), &courseinfopb.GetSemesterRequest{}); err != nil {
+		t.Errorf("GetSemester(current) error: %v", err)
+	}
+	_, err = client.GetSemester(t.Context(), &courseinfopb.GetSemesterRequest{Code: "2550"})
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("GetSemester(2550) code = %v, want %v", got, codes.InvalidArgument)
+	}
+}
+
+func TestGRPC_WatchChanges(t *testing.T) {
+	a := testApp()
+	client := courseinfopb.NewCourseInfoClient(grpcTestClient(t, a))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchChanges(ctx, &courseinfopb.WatchChangesRequest{Department: "COMP"})
+	if err != nil {
+		t.Fatalf("WatchChanges() error: %v", err)
+	}
+	// Wait for the server to register the watcher before changing the cache.
+	for {
+		a.watchersMu.Lock()
+		n := len(a.watchers)
+		a.watchersMu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	a.remember(&CourseParsingResult{Code: "MATH1013", Course: &Course{Code: "MATH1013"}})
+	a.remember(&CourseParsingResult{Code: "COMP1021", Course: &Course{Code: "COMP1021", Title: "Old"}})
+	a.remember(&CourseParsingResult{Code: "COMP1021", Course: &Course{Code: "COMP1021", Title: "Old"}})
+	a.remember(&CourseParsingResult{Code: "COMP1021", Course: &Course{Code: "COMP1021", Title: "New"}})
+
+	want := []courseinfopb.CourseChange_Type{
+		courseinfopb.CourseChange_TYPE_ADDED,
+		courseinfopb.CourseChange_TYPE_UPDATED,
+	}
+	for _, wantType := range want {
+		change, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv() error: %v", err)
+		}
+		if change.GetCourse().GetCode() != "COMP1021" || change.GetType() != wantType {
+			t.Errorf("change = %v %s, want %v COMP1021", change.GetType(), change.GetCourse().GetCode(), wantType)
+		}
+	}
+}
+
+func TestGRPC_Health(t *testing.T) {
+	a := testApp()
+	client := healthpb.NewHealthClient(grpcTestClient(t, a))
+	service := courseinfopb.CourseInfo_ServiceDesc.ServiceName
+
+	resp, err := client.Check(t.Context(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Errorf("status before ready = %v, want NOT_SERVING", resp.GetStatus())
+	}
+
+	a.markReady(time.Now())
+	resp, err = client.Check(t.Context(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("Check() error: %v", err)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("status after ready = %v, want SERVING", resp.GetStatus())
+	}
+}
@@ -1,12 +1,13 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"maps"
 	"net/http"
 	"slices"
-	"strings"
+	"time"
 	"unicode"
 
 	"github.com/labstack/echo/v4"
@@ -21,6 +22,16 @@ func extractDepartment(code string) string {
 	return code
 }
 
+// normalizeCourseCode canonicalizes a user-supplied course code and splits
+// off its department prefix.
+func normalizeCourseCode(raw string) (code, department string, err error) {
+	c, err := parseCourseCode(raw)
+	if err != nil {
+		return "", "", err
+	}
+	return c.String(), c.Department, nil
+}
+
 func (a *app) HandleIntrospection(c echo.Context) error {
 	m, err := a.manifest.MarshalJSON()
 	if err != nil {
@@ -41,28 +52,37 @@ func (a *app) HandleHealthCheck(c echo.Context) error {
 	return nil
 }
 
-func (a *app) HandleGetSemester(c echo.Context) error {
-	a.logger.Info("GET /v1/semesters", "semester", c.Param("semester"))
-	if c.Param("semester") != "current" {
-		s, err := parseSemester(c.Param("semester"))
-		if err != nil {
-			if errors.Is(err, ErrInvalidSemesterCode) {
-				c.JSON(http.StatusBadRequest, errorResponse{
-					Status:  "error",
-					Message: err.Error(),
-				})
-			} else {
-				c.JSON(http.StatusInternalServerError, errorResponse{
-					Status:  "error",
-					Message: err.Error(),
-				})
-			}
-			return nil
-		}
-		c.JSON(http.StatusOK, s)
+func (a *app) HandleReadinessCheck(c echo.Context) error {
+	a.mu.RLock()
+	ready := a.ready
+	resp := readyzResponse{
+		Status:        "ready",
+		CoursesCached: len(a.cache),
+		Upstream:      a.upstream,
+	}
+	if !a.lastRefresh.IsZero() {
+		lastRefresh := a.lastRefresh
+		resp.LastRefresh = &lastRefresh
+	}
+	a.mu.RUnlock()
+	switch {
+	case a.offline():
+		resp.Upstream = "offline"
+	case resp.Upstream == "":
+		resp.Upstream = "unknown"
+	}
+	if !ready {
+		resp.Status = "not ready"
+		c.JSON(http.StatusServiceUnavailable, resp)
 		return nil
 	}
-	currentSemester, err := getCurrentSemesterCode()
+	c.JSON(http.StatusOK, resp)
+	return nil
+}
+
+func (a *app) HandleGetSemester(c echo.Context) error {
+	a.logger.Info("GET /v1/semesters", "semester", c.Param("semester"))
+	code, err := a.resolveSemesterCode(c.Param("semester"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{
 			Status:  "error",
@@ -70,12 +90,19 @@ func (a *app) HandleGetSemester(c echo.Context) error {
 		})
 		return nil
 	}
-	s, err := parseSemester(currentSemester)
+	s, err := a.calendar.parseSemester(code)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, errorResponse{
-			Status:  "error",
-			Message: err.Error(),
-		})
+		if errors.Is(err, ErrInvalidSemesterCode) {
+			c.JSON(http.StatusBadRequest, errorResponse{
+				Status:  "error",
+				Message: err.Error(),
+			})
+		} else {
+			c.JSON(http.StatusInternalServerError, errorResponse{
+				Status:  "error",
+				Message: err.Error(),
+			})
+		}
 		return nil
 	}
 	c.JSON(http.StatusOK, s)
@@ -84,29 +111,43 @@ func (a *app) HandleGetSemester(c echo.Context) error {
 
 func (a *app) HandleGetCourse(c echo.Context) error {
 	a.logger.Info("GET /v1/courses/", "course", c.Param("course"))
-	courseCode := strings.ToUpper(c.Param("course"))
-	department := extractDepartment(courseCode)
-	if department == "" || department == courseCode {
+	courseCode, department, err := normalizeCourseCode(c.Param("course"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, errorResponse{
 			Status:  "error",
-			Message: "course code must have an alphabetic department prefix followed by a number",
+			Message: err.Error(),
 		})
 		return nil
 	}
-
-	a.mu.RLock()
-	if val, ok := a.cache[courseCode]; ok {
-		a.mu.RUnlock()
-		c.JSON(http.StatusOK, val)
+	fields, err := parseFields(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	term, err := a.requestedTerm(c)
+	if err != nil {
+		c.JSON(requestedTermStatus(err), errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
 		return nil
 	}
-	a.mu.RUnlock()
-
-	a.GetCourse(department)
 
-	a.mu.RLock()
-	val, ok := a.cache[courseCode]
-	a.mu.RUnlock()
+	var val *Course
+	var ok bool
+	var modified time.Time
+	if term != nil {
+		val, ok = term.course(courseCode)
+		modified = term.lastModified
+	} else if val, ok = a.lookupCourse(c.Request().Context(), courseCode, department); ok {
+		// An alias is modified along with the course it stands for.
+		a.mu.RLock()
+		modified = a.modified[val.Code]
+		a.mu.RUnlock()
+	}
 	if !ok {
 		c.JSON(http.StatusNotFound, errorResponse{
 			Status:  "error",
@@ -114,22 +155,175 @@ func (a *app) HandleGetCourse(c echo.Context) error {
 		})
 		return nil
 	}
-	c.JSON(http.StatusOK, val)
+	if a.checkNotModified(c, courseETag(representation(formatJSON, fields), val), modified) {
+		return nil
+	}
+	c.JSON(http.StatusOK, selectFields(val, fields))
+	return nil
+}
+
+func (a *app) HandleGetCourseOfferings(c echo.Context) error {
+	a.logger.Info("GET /v1/courses/:course/offerings", "course", c.Param("course"))
+	courseCode, _, err := normalizeCourseCode(c.Param("course"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	offerings, err := a.courseOfferings(c.Request().Context(), courseCode)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	if len(offerings) == 0 {
+		c.JSON(http.StatusNotFound, errorResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("no offerings of course %s found", courseCode),
+		})
+		return nil
+	}
+	c.JSON(http.StatusOK, courseOfferingsResponse{
+		Code:      courseCode,
+		Offerings: offerings,
+	})
 	return nil
 }
 
 func (a *app) HandleGetCourses(c echo.Context) error {
 	a.logger.Info("GET /v1/courses")
+	format, err := negotiateFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	fields, err := parseFields(c)
+	if err == nil && fields != nil && format == formatCSV {
+		err = ErrFieldsWithCSV
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	term, err := a.requestedTerm(c)
+	if err != nil {
+		c.JSON(requestedTermStatus(err), errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	var courses []*Course
+	var modified time.Time
+	if term != nil {
+		courses, modified = term.sortedCourses(), term.lastModified
+	} else {
+		courses = a.cachedCourses("")
+		a.mu.RLock()
+		modified = a.lastModified
+		a.mu.RUnlock()
+	}
+	if a.checkNotModified(c, courseETag(representation(format, fields), courses...), modified) {
+		return nil
+	}
+	return writeCourses(c, format, fields, courses)
+}
+
+// maxBatchGetCodes bounds the number of course codes accepted by a single
+// batch lookup.
+const maxBatchGetCodes = 100
+
+func (a *app) HandleBatchGetCourses(c echo.Context) error {
+	a.logger.Info("POST /v1/courses:batchGet")
+	var req batchGetCoursesRequest
+	if err := c.Bind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: "request body must be a JSON object with a codes array",
+		})
+		return nil
+	}
+	if len(req.Codes) > maxBatchGetCodes {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("at most %d course codes may be requested at once", maxBatchGetCodes),
+		})
+		return nil
+	}
+	c.JSON(http.StatusOK, a.batchGetCourses(c.Request().Context(), req.Codes))
+	return nil
+}
+
+// batchGetCourses looks up codes in the cache, scraping each distinct
+// department with missing courses once. Courses are returned in request
+// order; invalid and unknown codes are reported as not found.
+func (a *app) batchGetCourses(ctx context.Context, codes []string) batchGetCoursesResponse {
+	resp := batchGetCoursesResponse{
+		Courses:  []*Course{},
+		NotFound: []string{},
+	}
+	var normalized []string
+	seen := make(map[string]bool)
+	var missing []string
 	a.mu.RLock()
-	courses := slices.Collect(maps.Values(a.cache))
+	for _, raw := range codes {
+		code, department, err := normalizeCourseCode(raw)
+		if err != nil {
+			resp.NotFound = append(resp.NotFound, raw)
+			continue
+		}
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		normalized = append(normalized, code)
+		if _, ok := a.cachedCourseLocked(code); ok {
+			cacheLookups.WithLabelValues("hit").Inc()
+			continue
+		}
+		cacheLookups.WithLabelValues("miss").Inc()
+		if !slices.Contains(missing, department) {
+			missing = append(missing, department)
+		}
+	}
 	a.mu.RUnlock()
-	c.JSON(http.StatusOK, courses)
-	return nil
+
+	for _, department := range missing {
+		a.GetCourse(ctx, department)
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for _, code := range normalized {
+		if course, ok := a.cachedCourseLocked(code); ok {
+			resp.Courses = append(resp.Courses, course)
+		} else {
+			resp.NotFound = append(resp.NotFound, code)
+		}
+	}
+	return resp
 }
 
 func (a *app) HandleRefreshCourses(c echo.Context) error {
 	a.logger.Info("PATCH /v1/courses")
-	semester, err := getCurrentSemesterCode()
+	if a.offline() {
+		c.JSON(http.StatusMethodNotAllowed, errorResponse{
+			Status:  "error",
+			Message: ErrReadOnlyDataset.Error(),
+		})
+		return nil
+	}
+	semester, err := a.calendar.currentSemesterCode()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse{
 			Status:  "error",
@@ -137,8 +331,21 @@ func (a *app) HandleRefreshCourses(c echo.Context) error {
 		})
 		return nil
 	}
-	a.setEndpoint(fmt.Sprintf("%s/%s", a.config.BaseURL, semester))
-	a.PreCacheCurrentSemesterCourses()
+	endpoint, rollover := a.getEndpoint(), semester != a.semester()
+	if rollover {
+		endpoint = fmt.Sprintf("%s/%s", a.config.BaseURL, semester)
+	}
+	if err := a.precacheSemester(c.Request().Context(), endpoint, rollover); err != nil {
+		status := http.StatusBadGateway
+		if errors.Is(err, ErrSemesterChanged) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
 
 	a.mu.RLock()
 	courses := slices.Collect(maps.Values(a.cache))
@@ -146,3 +353,24 @@ func (a *app) HandleRefreshCourses(c echo.Context) error {
 	c.JSON(http.StatusOK, courses)
 	return nil
 }
+
+func (a *app) HandleGetDepartments(c echo.Context) error {
+	a.logger.Info("GET /v1/departments")
+	term, err := a.requestedTerm(c)
+	if err != nil {
+		c.JSON(requestedTermStatus(err), errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	if term != nil {
+		c.JSON(http.StatusOK, term.departments)
+		return nil
+	}
+	a.mu.RLock()
+	departments := slices.Clone(a.departmentCache)
+	a.mu.RUnlock()
+	c.JSON(http.StatusOK, departments)
+	return nil
+}
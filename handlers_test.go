@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -268,3 +273,164 @@ func TestExtractDepartment(t *testing.T) {
 func TestParseCourse(t *testing.T) {
 	t.Skip("requires refactoring: ParseCourse depends on colly.HTMLElement which is hard to construct in tests")
 }
+
+func TestHandleGetDepartments(t *testing.T) {
+	a := testApp()
+	a.departmentCache = []department{
+		{Code: "COMP", Name: "Computer Science and Engineering", Level: "ug"},
+	}
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/departments", a)
+
+	err := a.HandleGetDepartments(c)
+	if err != nil {
+		t.Fatalf("HandleGetDepartments() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var departments []department
+	if err := json.Unmarshal(rec.Body.Bytes(), &departments); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(departments) != 1 || departments[0].Name != "Computer Science and Engineering" {
+		t.Errorf("departments = %+v, want one COMP entry with full name", departments)
+	}
+}
+
+func TestHandleReadinessCheck_NotReady(t *testing.T) {
+	a := testApp()
+	a.endpoint = "http://127.0.0.1:1/invalid"
+	a.probeUpstream(context.Background())
+	c, rec := setupHandlerTest(http.MethodGet, "/readyz", a)
+
+	err := a.HandleReadinessCheck(c)
+	if err != nil {
+		t.Fatalf("HandleReadinessCheck() error: %v", err)
+	}
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	var resp readyzResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Status != "not ready" {
+		t.Errorf("status = %q, want %q", resp.Status, "not ready")
+	}
+	if resp.Upstream != "unreachable" {
+		t.Errorf("upstream = %q, want %q", resp.Upstream, "unreachable")
+	}
+	if resp.LastRefresh != nil {
+		t.Errorf("last_refresh = %v, want nil", resp.LastRefresh)
+	}
+}
+
+func TestHandleReadinessCheck_Ready(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	a := testApp()
+	a.endpoint = srv.URL
+	a.cache["COMP1021"] = &Course{Code: "COMP1021"}
+	refreshed := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	a.markReady(refreshed)
+	a.probeUpstream(context.Background())
+	// The upstream going away is only noticed by the next probe.
+	srv.Close()
+	c, rec := setupHandlerTest(http.MethodGet, "/readyz", a)
+
+	err := a.HandleReadinessCheck(c)
+	if err != nil {
+		t.Fatalf("HandleReadinessCheck() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	var resp readyzResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.CoursesCached != 1 {
+		t.Errorf("courses_cached = %d, want 1", resp.CoursesCached)
+	}
+	if resp.LastRefresh == nil || !resp.LastRefresh.Equal(refreshed) {
+		t.Errorf("last_refresh = %v, want %v", resp.LastRefresh, refreshed)
+	}
+	if resp.Upstream != "reachable" {
+		t.Errorf("upstream = %q, want %q", resp.Upstream, "reachable")
+	}
+}
+
+func TestHandleBatchGetCourses(t *testing.T) {
+	visits := make(map[string]int)
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		visits[r.URL.Path]++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/subject/COMP" {
+			fmt.Fprint(w, `<html><body><div class="course"><div class="courseinfo"><div class="courseattrContainer">
+				<div class="subject">COMP 2011 - Programming with C++ (4 units)</div>
+			</div></div></div></body></html>`)
+		}
+	}))
+	defer srv.Close()
+
+	a := routedApp()
+	a.endpoint = srv.URL
+	a.cache["COMP1021"] = &Course{Code: "COMP1021"}
+
+	body := `{"codes": ["COMP1021", "comp2011", "COMP9999", "MATH1013", "1234", "COMP2011"]}`
+	req := httptest.NewRequest(http.MethodPost, "/v1/courses:batchGet", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	a.server.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var resp batchGetCoursesResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	var found []string
+	for _, c := range resp.Courses {
+		found = append(found, c.Code)
+	}
+	if strings.Join(found, ",") != "COMP1021,COMP2011" {
+		t.Errorf("courses = %v, want [COMP1021 COMP2011]", found)
+	}
+	if strings.Join(resp.NotFound, ",") != "1234,COMP9999,MATH1013" {
+		t.Errorf("not_found = %v, want [1234 COMP9999 MATH1013]", resp.NotFound)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, path := range []string{"/subject/COMP", "/subject/MATH"} {
+		if visits[path] != 1 {
+			t.Errorf("visits[%s] = %d, want 1", path, visits[path])
+		}
+	}
+}
+
+func TestHandleBatchGetCourses_TooMany(t *testing.T) {
+	a := testApp()
+	codes := make([]string, maxBatchGetCodes+1)
+	for i := range codes {
+		codes[i] = fmt.Sprintf("COMP%04d", i)
+	}
+	body, _ := json.Marshal(batchGetCoursesRequest{Codes: codes})
+	c, rec := setupHandlerTest(http.MethodPost, "/v1/courses:batchGet", a)
+	c.SetRequest(httptest.NewRequest(http.MethodPost, "/v1/courses:batchGet", strings.NewReader(string(body))))
+	c.Request().Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	if err := a.HandleBatchGetCourses(c); err != nil {
+		t.Fatalf("HandleBatchGetCourses() error: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// courseETag returns a strong entity tag for the given representation of
+// courses, derived from their content so that every replica serving the same
+// catalogue agrees on it.
+func courseETag(format string, courses ...*Course) string {
+	h := sha256.New()
+	h.Write([]byte(format))
+	for _, course := range courses {
+		writeCourseDigest(h, course)
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+func writeCourseDigest(h hash.Hash, course *Course) {
+	// Marshalling a Course cannot fail: it holds only strings, numbers and
+	// maps keyed by strings, which encoding/json writes in sorted order.
+	b, _ := json.Marshal(course)
+	h.Write(b)
+	h.Write([]byte{'\n'})
+}
+
+// cacheControl returns the Cache-Control value for cached course data,
+// allowing clients to reuse a response until the next scheduled refresh.
+func (a *app) cacheControl() string {
+	a.mu.RLock()
+	lastRefresh, nextRefresh := a.lastRefresh, a.nextRefresh
+	a.mu.RUnlock()
+	var maxAge time.Duration
+	switch {
+	case !nextRefresh.IsZero():
+		maxAge = time.Until(nextRefresh)
+	case a.config.RefreshInterval <= 0:
+		return "no-cache"
+	case lastRefresh.IsZero():
+		maxAge = a.config.RefreshInterval
+	default:
+		maxAge = time.Until(lastRefresh.Add(a.config.RefreshInterval))
+	}
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	return fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds()))
+}
+
+// checkNotModified sets the validator and Cache-Control headers on the
+// response and, if the request's conditional headers show the client already
+// holds this representation, writes 304 Not Modified and reports true.
+func (a *app) checkNotModified(c echo.Context, etag string, modified time.Time) bool {
+	header := c.Response().Header()
+	header.Set(echo.HeaderCacheControl, a.cacheControl())
+	header.Set("ETag", etag)
+	if !modified.IsZero() {
+		header.Set(echo.HeaderLastModified, modified.UTC().Format(http.TimeFormat))
+	}
+
+	req := c.Request()
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		if etagMatches(inm, etag) {
+			c.NoContent(http.StatusNotModified)
+			return true
+		}
+		// If-Modified-Since is ignored when If-None-Match is present.
+		return false
+	}
+	if ims := req.Header.Get(echo.HeaderIfModifiedSince); ims != "" && !modified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err == nil && !modified.Truncate(time.Second).After(t) {
+			c.NoContent(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag
+// using the weak comparison required for GET requests.
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for candidate := range strings.SplitSeq(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestCourseETag(t *testing.T) {
+	a := &Course{Code: "COMP1021", Title: "Intro", Instructors: map[string][]string{"A": {"L1"}, "B": {"L2"}}}
+	b := &Course{Code: "COMP1021", Title: "Intro", Instructors: map[string][]string{"B": {"L2"}, "A": {"L1"}}}
+	if courseETag(formatJSON, a) != courseETag(formatJSON, b) {
+		t.Error("equal courses produced different ETags")
+	}
+	if courseETag(formatJSON, a) == courseETag(formatCSV, a) {
+		t.Error("different formats produced the same ETag")
+	}
+	changed := &Course{Code: "COMP1021", Title: "Intro (Updated)"}
+	if courseETag(formatJSON, a) == courseETag(formatJSON, changed) {
+		t.Error("different courses produced the same ETag")
+	}
+}
+
+func getCourseWithHeaders(t *testing.T, a *app, headers map[string]string) (int, http.Header) {
+	t.Helper()
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/courses/COMP1021", a)
+	c.SetParamNames("course")
+	c.SetParamValues("COMP1021")
+	for k, v := range headers {
+		c.Request().Header.Set(k, v)
+	}
+	if err := a.HandleGetCourse(c); err != nil {
+		t.Fatalf("HandleGetCourse() error: %v", err)
+	}
+	return rec.Code, rec.Header()
+}
+
+func TestHandleGetCourse_ConditionalGET(t *testing.T) {
+	a := testApp()
+	a.config.RefreshInterval = time.Hour
+	a.remember(&CourseParsingResult{Code: "COMP1021", Course: &Course{Code: "COMP1021", Title: "Intro"}})
+
+	code, header := getCourseWithHeaders(t, a, nil)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", code, http.StatusOK)
+	}
+	etag := header.Get("ETag")
+	if etag == "" {
+		t.Fatal("response has no ETag")
+	}
+	lastModified := header.Get(echo.HeaderLastModified)
+	if lastModified == "" {
+		t.Fatal("response has no Last-Modified")
+	}
+	if got := header.Get(echo.HeaderCacheControl); got != "public, max-age=3600" {
+		t.Errorf("Cache-Control = %q, want %q", got, "public, max-age=3600")
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"matching etag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"etag in list", map[string]string{"If-None-Match": `"other", ` + etag}, http.StatusNotModified},
+		{"weak etag", map[string]string{"If-None-Match": "W/" + etag}, http.StatusNotModified},
+		{"stale etag", map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		{"not modified since", map[string]string{echo.HeaderIfModifiedSince: lastModified}, http.StatusNotModified},
+		{"modified since", map[string]string{echo.HeaderIfModifiedSince: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)}, http.StatusOK},
+		{"etag takes precedence", map[string]string{"If-None-Match": `"other"`, echo.HeaderIfModifiedSince: lastModified}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, _ := getCourseWithHeaders(t, a, tt.headers); code != tt.want {
+				t.Errorf("status = %d, want %d", code, tt.want)
+			}
+		})
+	}
+
+	a.remember(&CourseParsingResult{Code: "COMP1021", Course: &Course{Code: "COMP1021", Title: "Intro (Updated)"}})
+	if code, _ := getCourseWithHeaders(t, a, map[string]string{"If-None-Match": etag}); code != http.StatusOK {
+		t.Errorf("status after update = %d, want %d", code, http.StatusOK)
+	}
+}
+
+func TestHandleGetCourses_ConditionalGET(t *testing.T) {
+	a := fixtureApp()
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/courses", a)
+	if err := a.HandleGetCourses(c); err != nil {
+		t.Fatalf("HandleGetCourses() error: %v", err)
+	}
+	etag := rec.Header().Get("ETag")
+
+	c, rec = setupHandlerTest(http.MethodGet, "/v1/courses", a)
+	c.Request().Header.Set("If-None-Match", etag)
+	if err := a.HandleGetCourses(c); err != nil {
+		t.Fatalf("HandleGetCourses() error: %v", err)
+	}
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotModified)
+	}
+
+	c, rec = setupHandlerTest(http.MethodGet, "/v1/courses?format=csv", a)
+	c.Request().Header.Set("If-None-Match", etag)
+	if err := a.HandleGetCourses(c); err != nil {
+		t.Fatalf("HandleGetCourses() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status for CSV with JSON ETag = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestCacheControl(t *testing.T) {
+	a := testApp()
+	if got := a.cacheControl(); got != "no-cache" {
+		t.Errorf("cacheControl() without refresh interval = %q, want no-cache", got)
+	}
+	a.config.RefreshInterval = 2 * time.Hour
+	a.markReady(time.Now().Add(-time.Hour))
+	if got := a.cacheControl(); got != "public, max-age=3599" && got != "public, max-age=3600" {
+		t.Errorf("cacheControl() = %q, want about an hour", got)
+	}
+	a.markReady(time.Now().Add(-3 * time.Hour))
+	if got := a.cacheControl(); got != "public, max-age=0" {
+		t.Errorf("cacheControl() past refresh = %q, want max-age=0", got)
+	}
+	// A scheduled refresh takes precedence over the fixed interval.
+	a.nextRefresh = time.Now().Add(30*time.Minute + time.Second)
+	if got := a.cacheControl(); got != "public, max-age=1800" {
+		t.Errorf("cacheControl() with scheduled refresh = %q, want max-age=1800", got)
+	}
+}
@@ -2,65 +2,62 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
+	"maps"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
 )
 
-type config struct {
-	Port            string
-	MetricsPort     string
-	BaseURL         string
-	RefreshInterval time.Duration
-}
-
-func loadConfig() config {
-	cfg := config{
-		Port:            ":8080",
-		MetricsPort:     ":2112",
-		BaseURL:         "https://w5.ab.ust.hk/wcq/cgi-bin",
-		RefreshInterval: 7 * 24 * time.Hour,
-	}
-	if v := os.Getenv("PORT"); v != "" {
-		cfg.Port = ":" + v
-	}
-	if v := os.Getenv("METRICS_PORT"); v != "" {
-		cfg.MetricsPort = ":" + v
-	}
-	if v := os.Getenv("BASE_URL"); v != "" {
-		cfg.BaseURL = v
-	}
-	if v := os.Getenv("REFRESH_INTERVAL"); v != "" {
-		if d, err := time.ParseDuration(v); err == nil {
-			cfg.RefreshInterval = d
-		}
-	}
-	return cfg
-}
-
 type app struct {
 	config          config
 	endpoint        string
 	cache           map[string]*Course
-	departmentCache []string
+	departmentCache []department
 	mu              sync.RWMutex
 	server          *echo.Echo
 	metricsServer   *http.Server
 	logger          *slog.Logger
 	manifest        *buildInfo
+	ready           bool
+	lastRefresh     time.Time
+	nextRefresh     time.Time
+	previous        *archivedTerm
+	calendar        *academicCalendar
+	snapshots       *snapshotStore
+	store           *courseStore
+	grpcServer      *grpc.Server
+	health          *health.Server
+	modified        map[string]time.Time
+	lastModified    time.Time
+	upstream        string            // result of the last upstream probe
+	semesters       []string          // discovered semester codes, nil until discovered
+	aliases         map[string]string // cross-listed code to cached code
+	search          *searchIndex
+	suggestions     *suggestIndex
+	watchers        map[chan courseChange]struct{}
+	watchersMu      sync.Mutex
 }
 
-func NewApp(logger *slog.Logger) *app {
-	cfg := loadConfig()
+func NewApp(cfg config, logger *slog.Logger) *app {
 	manifest := Manifest()
 	fmt.Print(manifest.String())
 	logger.Info("Initializing application...")
@@ -68,7 +65,27 @@ func NewApp(logger *slog.Logger) *app {
 	e.HideBanner = true
 	e.Pre(middleware.RemoveTrailingSlash())
 	e.Use(middleware.Logger())
-	currentSemester, err := getCurrentSemesterCode()
+	e.Use(requestTracing)
+	e.Use(requestMetrics)
+	e.Use(compression)
+	if cfg.ValidateRequests {
+		doc, err := loadOpenAPISpec()
+		if err != nil {
+			logger.Error("error while loading OpenAPI spec", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		validate, err := requestValidation(doc)
+		if err != nil {
+			logger.Error("error while setting up request validation", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		e.Use(validate)
+	}
+	cal := cfg.Calendar
+	if cal == nil {
+		cal = defaultCalendar()
+	}
+	currentSemester, err := cal.currentSemesterCode()
 	if err != nil {
 		logger.Error("error while getting current semester code", slog.String("error", err.Error()))
 		os.Exit(1)
@@ -77,19 +94,56 @@ func NewApp(logger *slog.Logger) *app {
 	metricsMux := http.NewServeMux()
 	metricsMux.Handle("/metrics", promhttp.Handler())
 
-	return &app{
+	a := &app{
 		config:          cfg,
 		endpoint:        fmt.Sprintf("%s/%s", cfg.BaseURL, currentSemester),
 		server:          e,
 		cache:           make(map[string]*Course),
-		departmentCache: []string{},
+		departmentCache: []department{},
 		metricsServer: &http.Server{
 			Addr:    cfg.MetricsPort,
 			Handler: metricsMux,
 		},
 		logger:   logger,
 		manifest: manifest,
+		calendar: cal,
+		health:   health.NewServer(),
+	}
+	if cfg.SnapshotDir != "" {
+		a.snapshots, err = openSnapshotStore(cfg.SnapshotDir)
+		if err != nil {
+			logger.Error("error while opening snapshot store", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		if a.loadSnapshot() {
+			logger.Info("Serving snapshot until the first crawl", slog.String("semester", a.semester()))
+		}
 	}
+	if cfg.Database != "" {
+		a.store, err = openStore(context.Background(), cfg.Database)
+		if err != nil {
+			logger.Error("error while opening database", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		// The database holds the complete catalogue, unlike snapshots, so
+		// it is preferred.
+		loaded, err := a.loadStoredSemester(context.Background())
+		if err != nil {
+			logger.Error("error while loading stored semester", slog.String("error", err.Error()))
+		} else if loaded {
+			logger.Info("Serving stored semester until the first crawl", slog.String("semester", a.semester()))
+		}
+	}
+	if cfg.Dataset != "" {
+		ds, err := readDataset(cfg.Dataset)
+		if err != nil {
+			logger.Error("error while loading dataset", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		a.loadDataset(ds)
+	}
+	a.grpcServer = a.newGRPCServer()
+	return a
 }
 
 func (a *app) getEndpoint() string {
@@ -98,25 +152,204 @@ func (a *app) getEndpoint() string {
 	return a.endpoint
 }
 
-func (a *app) setEndpoint(endpoint string) {
-	a.mu.Lock()
-	defer a.mu.Unlock()
-	a.endpoint = endpoint
+// semester returns the code of the semester the app is currently scraping.
+func (a *app) semester() string {
+	return path.Base(a.getEndpoint())
 }
 
 func (a *app) remember(r *CourseParsingResult) {
 	a.mu.Lock()
+	previous := a.cache[r.Code]
 	a.cache[r.Code] = r.Course
+	// The course may have dropped co-listings since it was last remembered.
+	maps.DeleteFunc(a.aliases, func(_, code string) bool { return code == r.Code })
+	a.indexCourseLocked(r.Code, r.Course)
+	if previous == nil || !reflect.DeepEqual(previous, r.Course) {
+		now := time.Now()
+		if a.modified == nil {
+			a.modified = make(map[string]time.Time)
+		}
+		a.modified[r.Code] = now
+		a.lastModified = now
+	}
+	cached := len(a.cache)
 	a.mu.Unlock()
+	a.notify(previous, r.Course)
+	coursesCached.WithLabelValues(a.semester()).Set(float64(cached))
 	a.logger.Info("In-memory cache updated for", "courseCode", r.Code)
 }
 
+// indexCourseLocked registers a cached course's co-listings as aliases and
+// adds it to the search and suggestion indexes. a.mu must be held.
+func (a *app) indexCourseLocked(code string, course *Course) {
+	for _, alias := range course.CoListWith {
+		if a.aliases == nil {
+			a.aliases = make(map[string]string)
+		}
+		if _, ok := a.aliases[alias]; !ok {
+			a.aliases[alias] = code
+		}
+	}
+	if a.search == nil {
+		a.search = newSearchIndex()
+	}
+	a.search.add(course)
+	if a.suggestions == nil {
+		a.suggestions = newSuggestIndex()
+	}
+	a.suggestions.add(course)
+}
+
+// replaceCourses swaps in the catalogue of a completed crawl, reporting
+// whether it did. A crawl of another semester than the current one rolls over
+// to it if the crawl is a rollover, and is discarded otherwise, so that a
+// crawl of the old term finishing late cannot undo a rollover. Courses that
+// did not change keep their modification time, and those that did are
+// modified as of the crawl. Watchers hear about courses that were added,
+// changed or removed, compared with the catalogue served before, even across
+// a rollover.
+func (a *app) replaceCourses(c *crawl) bool {
+	type update struct{ previous, course *Course }
+	var updates []update
+	now := c.crawledAt
+	a.mu.Lock()
+	served := a.cache
+	from := ""
+	if c.endpoint != a.endpoint {
+		if !c.rollover {
+			a.mu.Unlock()
+			return false
+		}
+		from = a.switchSemesterLocked(c.endpoint)
+	}
+	previous, previousModified := a.cache, a.modified
+	a.clearCoursesLocked()
+	a.departmentCache = c.departments
+	a.modified = make(map[string]time.Time, len(c.courses))
+	changed := len(previous) != len(c.courses)
+	for _, code := range slices.Sorted(maps.Keys(c.courses)) {
+		course := c.courses[code]
+		a.cache[code] = course
+		a.indexCourseLocked(code, course)
+		if old, ok := previous[code]; ok && reflect.DeepEqual(old, course) {
+			a.modified[code] = previousModified[code]
+			continue
+		}
+		a.modified[code] = now
+		changed = true
+	}
+	for _, code := range slices.Sorted(maps.Keys(served)) {
+		if _, ok := c.courses[code]; !ok {
+			updates = append(updates, update{served[code], nil})
+		}
+	}
+	for _, code := range slices.Sorted(maps.Keys(c.courses)) {
+		if !reflect.DeepEqual(served[code], c.courses[code]) {
+			updates = append(updates, update{served[code], c.courses[code]})
+		}
+	}
+	if changed {
+		a.lastModified = now
+	}
+	cached := len(a.cache)
+	a.mu.Unlock()
+	if from != "" {
+		semesterRollovers.Inc()
+		a.logger.Info("Semester rollover", slog.String("from", from), slog.String("to", a.semester()))
+	}
+	for _, u := range updates {
+		a.notify(u.previous, u.course)
+	}
+	coursesCached.WithLabelValues(a.semester()).Set(float64(cached))
+	return true
+}
+
+// lookupCourse returns the cached course with the given code, scraping its
+// department first if the course is not cached yet.
+func (a *app) lookupCourse(ctx context.Context, courseCode, department string) (*Course, bool) {
+	_, span := startSpan(ctx, "cache.lookup", trace.WithAttributes(attrCourseCode.String(courseCode)))
+	a.mu.RLock()
+	val, ok := a.cachedCourseLocked(courseCode)
+	a.mu.RUnlock()
+	span.SetAttributes(attribute.Bool("courseinfo.cache.hit", ok))
+	span.End()
+	if ok {
+		cacheLookups.WithLabelValues("hit").Inc()
+		return val, true
+	}
+	cacheLookups.WithLabelValues("miss").Inc()
+
+	a.GetCourse(ctx, department)
+
+	a.mu.RLock()
+	val, ok = a.cachedCourseLocked(courseCode)
+	a.mu.RUnlock()
+	return val, ok
+}
+
+// cachedCourseLocked returns the cached course with the given code, or the
+// course it is cross-listed with. a.mu must be held.
+func (a *app) cachedCourseLocked(code string) (*Course, bool) {
+	if course, ok := a.cache[code]; ok {
+		return course, true
+	}
+	course, ok := a.cache[a.aliases[code]]
+	return course, ok
+}
+
+// clearCoursesLocked empties the course cache along with the aliases, indexes
+// and modification times derived from it. a.mu must be held.
+func (a *app) clearCoursesLocked() {
+	a.cache = make(map[string]*Course)
+	a.aliases = nil
+	a.search = nil
+	a.suggestions = nil
+	a.modified = nil
+}
+
+// cachedCourses returns the cached courses of the given department, or every
+// cached course if department is empty, sorted by code.
+func (a *app) cachedCourses(department string) []*Course {
+	a.mu.RLock()
+	var courses []*Course
+	for code, course := range a.cache {
+		if department == "" || extractDepartment(code) == department {
+			courses = append(courses, course)
+		}
+	}
+	a.mu.RUnlock()
+	slices.SortFunc(courses, func(x, y *Course) int {
+		return strings.Compare(x.Code, y.Code)
+	})
+	return courses
+}
+
+// markReady records a completed crawl at t and marks the app as ready to
+// serve traffic.
+func (a *app) markReady(t time.Time) {
+	a.mu.Lock()
+	a.ready = true
+	a.lastRefresh = t
+	a.mu.Unlock()
+	a.setServing()
+}
+
 func (a *app) Start() error {
 	go func() {
 		if err := a.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			a.logger.Error("Metrics server error", slog.String("error", err.Error()))
 		}
 	}()
+	go func() {
+		lis, err := net.Listen("tcp", a.config.GRPCPort)
+		if err != nil {
+			a.logger.Error("gRPC server error", slog.String("error", err.Error()))
+			return
+		}
+		if err := a.grpcServer.Serve(lis); err != nil {
+			a.logger.Error("gRPC server error", slog.String("error", err.Error()))
+		}
+	}()
 	err := a.server.Start(a.config.Port)
 	if err != nil {
 		return err
@@ -128,32 +361,64 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
+	cfg, err := loadConfig(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "configuration error:", err)
+		os.Exit(2)
+	}
+	if cfg.PrintConfig {
+		if err := cfg.writeTo(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(cfg.Command) > 0 {
+		err := runCommand(ctx, cfg, cfg.Command, os.Stdout, os.Stderr)
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", cfg.Command[0], err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	var precache bool
-	flag.BoolVar(&precache, "precache", false, "Pre-cache current semester courses")
-	flag.Parse()
-	a := NewApp(logger)
+	a := NewApp(cfg, logger)
+	shutdownTracing, err := setupTracing(ctx, a.config, a.manifest)
+	if err != nil {
+		logger.Error("error while setting up tracing", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 	a.routes()
-	if precache {
-		a.PreCacheCurrentSemesterCourses()
+	switch {
+	case a.offline():
+		// The dataset was loaded by NewApp.
+	case cfg.Precache:
+		go a.precacheUntilReady(ctx, precacheRetryMin)
+	default:
+		// Without a precache crawl courses are fetched lazily on demand, so
+		// there is nothing to wait for before accepting traffic.
+		a.mu.Lock()
+		a.ready = true
+		a.mu.Unlock()
+		a.setServing()
 	}
-	go func() {
-		ticker := time.NewTicker(a.config.RefreshInterval)
-		defer ticker.Stop()
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case <-ticker.C:
-				logger.Info("Refreshing course cache (weekly)")
-				a.mu.Lock()
-				a.cache = make(map[string]*Course)
-				a.departmentCache = []string{}
-				a.mu.Unlock()
-				a.PreCacheCurrentSemesterCourses()
-			}
+	if !a.offline() {
+		schedule, err := newRefreshSchedule(a.config, a.calendar)
+		if err != nil {
+			logger.Error("error while setting up refresh schedule", slog.String("error", err.Error()))
+			os.Exit(1)
 		}
-	}()
+		go a.runRefreshLoop(ctx, schedule)
+		go a.runRolloverLoop(ctx)
+		go a.runUpstreamProbeLoop(ctx)
+	}
 
 	go func() {
 		if err := a.Start(); err != http.ErrServerClosed {
@@ -171,4 +436,13 @@ func main() {
 	if err := a.server.Shutdown(shutdownCtx); err != nil {
 		logger.Error("Server shutdown error", slog.String("error", err.Error()))
 	}
+	a.stopGRPCServer(shutdownCtx)
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("Tracing shutdown error", slog.String("error", err.Error()))
+	}
+	if a.store != nil {
+		if err := a.store.Close(); err != nil {
+			logger.Error("Database close error", slog.String("error", err.Error()))
+		}
+	}
 }
@@ -8,43 +8,6 @@ import (
 	"time"
 )
 
-func TestLoadConfig_Defaults(t *testing.T) {
-	cfg := loadConfig()
-	if cfg.Port != ":8080" {
-		t.Errorf("Port = %q, want %q", cfg.Port, ":8080")
-	}
-	if cfg.MetricsPort != ":2112" {
-		t.Errorf("MetricsPort = %q, want %q", cfg.MetricsPort, ":2112")
-	}
-	if cfg.BaseURL != "https://w5.ab.ust.hk/wcq/cgi-bin" {
-		t.Errorf("BaseURL = %q, want default", cfg.BaseURL)
-	}
-	if cfg.RefreshInterval != 7*24*time.Hour {
-		t.Errorf("RefreshInterval = %v, want %v", cfg.RefreshInterval, 7*24*time.Hour)
-	}
-}
-
-func TestLoadConfig_FromEnv(t *testing.T) {
-	t.Setenv("PORT", "9090")
-	t.Setenv("METRICS_PORT", "3000")
-	t.Setenv("BASE_URL", "https://example.com")
-	t.Setenv("REFRESH_INTERVAL", "1h")
-
-	cfg := loadConfig()
-	if cfg.Port != ":9090" {
-		t.Errorf("Port = %q, want %q", cfg.Port, ":9090")
-	}
-	if cfg.MetricsPort != ":3000" {
-		t.Errorf("MetricsPort = %q, want %q", cfg.MetricsPort, ":3000")
-	}
-	if cfg.BaseURL != "https://example.com" {
-		t.Errorf("BaseURL = %q, want %q", cfg.BaseURL, "https://example.com")
-	}
-	if cfg.RefreshInterval != time.Hour {
-		t.Errorf("RefreshInterval = %v, want %v", cfg.RefreshInterval, time.Hour)
-	}
-}
-
 func TestRemember(t *testing.T) {
 	a := testApp()
 
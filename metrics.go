@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	departmentsDiscovered = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "courseinfo",
+		Name:      "departments_discovered",
+		Help:      "Number of departments found on the semester index by the last successful discovery.",
+	})
+	departmentDiscoveryFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "courseinfo",
+		Name:      "department_discovery_failures_total",
+		Help:      "Department discoveries rejected because the list was empty or shrank sharply.",
+	})
+	scrapeDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "courseinfo",
+		Name:      "scrape_duration_seconds",
+		Help:      "Time taken to scrape a department's subject page.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+	}, []string{"department"})
+	pagesFetched = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "courseinfo",
+		Name:      "pages_fetched_total",
+		Help:      "Upstream pages fetched by the scraper, by HTTP status code.",
+	}, []string{"code"})
+	parseFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "courseinfo",
+		Name:      "parse_failures_total",
+		Help:      "Course blocks that could not be parsed.",
+	})
+	coursesCached = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "courseinfo",
+		Name:      "courses_cached",
+		Help:      "Number of courses held in the in-memory cache, by semester.",
+	}, []string{"semester"})
+	cacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "courseinfo",
+		Name:      "cache_lookups_total",
+		Help:      "Course cache lookups made while serving requests, by result.",
+	}, []string{"result"})
+	refreshDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "courseinfo",
+		Name:      "refresh_duration_seconds",
+		Help:      "Time taken by a full semester refresh crawl.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	})
+	refreshLastSuccess = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "courseinfo",
+		Name:      "refresh_last_success_timestamp_seconds",
+		Help:      "Unix time of the last refresh crawl that completed successfully.",
+	})
+	refreshNext = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "courseinfo",
+		Name:      "refresh_next_timestamp_seconds",
+		Help:      "Unix time at which the next scheduled refresh crawl starts.",
+	})
+	semesterRollovers = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "courseinfo",
+		Name:      "semester_rollovers_total",
+		Help:      "Switches to a new semester made when the current term changed.",
+	})
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "courseinfo",
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of API requests, by method, route and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "code"})
+)
+
+// requestMetrics records request latency labelled by the matched route
+// pattern rather than the raw path, keeping label cardinality bounded.
+func requestMetrics(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+		status := c.Response().Status
+		if err != nil {
+			if he, ok := err.(*echo.HTTPError); ok {
+				status = he.Code
+			}
+		}
+		requestDuration.WithLabelValues(c.Request().Method, c.Path(), strconv.Itoa(status)).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
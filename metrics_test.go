@@ -0,0 +1,62 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRequestMetrics_LabelsByRoute(t *testing.T) {
+	e := echo.New()
+	e.Use(requestMetrics)
+	e.GET("/v1/courses/:course", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	before := testutil.CollectAndCount(requestDuration)
+	for _, code := range []string{"COMP1021", "MATH1013"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/courses/"+code, nil)
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	// Both requests share one route pattern, so only one series is added.
+	if got := testutil.CollectAndCount(requestDuration) - before; got != 1 {
+		t.Errorf("new series = %d, want 1", got)
+	}
+}
+
+func TestHandleGetCourse_CacheMetrics(t *testing.T) {
+	a := testApp()
+	a.endpoint = "http://127.0.0.1:1/invalid"
+	a.cache["COMP1021"] = &Course{Code: "COMP1021"}
+
+	hits := testutil.ToFloat64(cacheLookups.WithLabelValues("hit"))
+	misses := testutil.ToFloat64(cacheLookups.WithLabelValues("miss"))
+	for _, code := range []string{"COMP1021", "COMP9999"} {
+		c, _ := setupHandlerTest(http.MethodGet, "/v1/courses/"+code, a)
+		c.SetParamNames("course")
+		c.SetParamValues(code)
+		if err := a.HandleGetCourse(c); err != nil {
+			t.Fatalf("HandleGetCourse(%s) error: %v", code, err)
+		}
+	}
+	if got := testutil.ToFloat64(cacheLookups.WithLabelValues("hit")) - hits; got != 1 {
+		t.Errorf("cache hits = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(cacheLookups.WithLabelValues("miss")) - misses; got != 1 {
+		t.Errorf("cache misses = %v, want 1", got)
+	}
+}
+
+func TestRemember_CoursesCachedMetric(t *testing.T) {
+	a := testApp()
+	a.endpoint = "https://example.com/wcq/cgi-bin/2510"
+	a.remember(&CourseParsingResult{Code: "COMP1021", Course: &Course{Code: "COMP1021"}})
+	a.remember(&CourseParsingResult{Code: "COMP2011", Course: &Course{Code: "COMP2011"}})
+
+	if got := testutil.ToFloat64(coursesCached.WithLabelValues("2510")); got != 2 {
+		t.Errorf("courses_cached{semester=2510} = %v, want 2", got)
+	}
+}
@@ -15,6 +15,28 @@ type Course struct {
 	Credits     float64             `json:"credits"`
 	Instructors map[string][]string `json:"instructors"`
 	Sections    []string            `json:"sections"`
+	Schedule    []Section           `json:"schedule,omitempty"`
+	// CoListWith holds the codes of cross-listed equivalents of the course.
+	CoListWith  []string `json:"co_list_with,omitempty"`
+	Description string   `json:"description,omitempty"`
+	// Prerequisites is the prerequisite rule as published, such as
+	// "COMP1021 OR COMP1022P".
+	Prerequisites string `json:"prerequisites,omitempty"`
+	// Exclusions holds the codes of courses that cannot be taken for credit
+	// together with the course.
+	Exclusions []string `json:"exclusions,omitempty"`
+}
+
+// Section holds the meeting time, room and enrolment figures of one
+// section of a course.
+type Section struct {
+	Code  string `json:"code"`
+	Time  string `json:"time"`
+	Room  string `json:"room"`
+	Quota int    `json:"quota"`
+	Enrol int    `json:"enrol"`
+	Avail int    `json:"avail"`
+	Wait  int    `json:"wait"`
 }
 
 type CourseParsingResult struct {
@@ -22,6 +44,81 @@ type CourseParsingResult struct {
 	Course *Course
 }
 
+type batchGetCoursesRequest struct {
+	Codes []string `json:"codes"`
+}
+
+type batchGetCoursesResponse struct {
+	Courses  []*Course `json:"courses"`
+	NotFound []string  `json:"not_found"`
+}
+
+type courseOfferingsResponse struct {
+	Code      string     `json:"code"`
+	Offerings []offering `json:"offerings"`
+}
+
+type searchResult struct {
+	Code    string  `json:"code"`
+	Title   string  `json:"title"`
+	Credits float64 `json:"credits"`
+	Score   float64 `json:"score"`
+}
+
+type searchResponse struct {
+	Query   string         `json:"query"`
+	Results []searchResult `json:"results"`
+}
+
+type suggestion struct {
+	Code    string  `json:"code"`
+	Title   string  `json:"title"`
+	Credits float64 `json:"credits"`
+}
+
+type suggestResponse struct {
+	Prefix      string       `json:"prefix"`
+	Suggestions []suggestion `json:"suggestions"`
+}
+
+type courseComparison struct {
+	Courses       []string           `json:"courses"`
+	Titles        valueDiff[string]  `json:"titles"`
+	Credits       valueDiff[float64] `json:"credits"`
+	Prerequisites valueDiff[string]  `json:"prerequisites"`
+	Exclusions    setDiff            `json:"exclusions"`
+	SectionTimes  setDiff            `json:"section_times"`
+	Instructors   setDiff            `json:"instructors"`
+	// Quotas holds the total quota of all sections of each course.
+	Quotas valueDiff[int] `json:"quotas"`
+}
+
+type requirementResult struct {
+	Name     string `json:"name"`
+	Required int    `json:"required"`
+	Met      bool   `json:"met"`
+	// SatisfiedBy holds the completed courses counted towards the
+	// requirement.
+	SatisfiedBy []string `json:"satisfied_by"`
+	// Options holds the courses offered this term that could count towards
+	// an unmet requirement.
+	Options []string `json:"options"`
+}
+
+// exclusionConflict is a completed course that does not count because an
+// earlier completed course excludes it.
+type exclusionConflict struct {
+	Course     string `json:"course"`
+	ExcludedBy string `json:"excluded_by"`
+}
+
+type requirementsCheck struct {
+	Program      string              `json:"program"`
+	Met          bool                `json:"met"`
+	Requirements []requirementResult `json:"requirements"`
+	Conflicts    []exclusionConflict `json:"conflicts"`
+}
+
 type buildInfo struct {
 	Name        string
 	Runtime     string
@@ -37,6 +134,13 @@ type healthzResponse struct {
 	Status string `json:"status"`
 }
 
+type readyzResponse struct {
+	Status        string     `json:"status"`
+	CoursesCached int        `json:"courses_cached"`
+	LastRefresh   *time.Time `json:"last_refresh,omitempty"`
+	Upstream      string     `json:"upstream"`
+}
+
 type errorResponse struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
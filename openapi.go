@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+)
+
+const mimeYAML = "application/yaml"
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+func loadOpenAPISpec() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(openAPISpec)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: loading spec: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("openapi: invalid spec: %w", err)
+	}
+	return doc, nil
+}
+
+func (a *app) HandleGetOpenAPI(c echo.Context) error {
+	c.JSONBlob(http.StatusOK, openAPISpec)
+	return nil
+}
+
+// requestValidation rejects requests whose path parameters, query string or
+// body do not match the OpenAPI document. Requests for paths the document
+// does not describe are passed through so echo can answer them as usual.
+func requestValidation(doc *openapi3.T) (echo.MiddlewareFunc, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: building router: %w", err)
+	}
+	// Bodies are encoded again once defaults from the document are filled
+	// in, and kin-openapi decodes YAML but cannot encode it.
+	openapi3filter.RegisterBodyEncoder(mimeYAML, yaml.Marshal)
+	options := &openapi3filter.Options{
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			route, pathParams, err := router.FindRoute(req)
+			if err != nil {
+				if err == routers.ErrPathNotFound || err == routers.ErrMethodNotAllowed {
+					return next(c)
+				}
+				c.JSON(http.StatusBadRequest, errorResponse{
+					Status:  "error",
+					Message: err.Error(),
+				})
+				return nil
+			}
+			// The router leaves path parameters escaped, so that "COMP 1021"
+			// would be checked as "COMP%201021".
+			for name, value := range pathParams {
+				if unescaped, err := url.PathUnescape(value); err == nil {
+					pathParams[name] = unescaped
+				}
+			}
+			err = openapi3filter.ValidateRequest(req.Context(), &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			})
+			if err != nil {
+				c.JSON(http.StatusBadRequest, errorResponse{
+					Status:  "error",
+					Message: err.Error(),
+				})
+				return nil
+			}
+			return next(c)
+		}
+	}, nil
+}
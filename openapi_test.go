@@ -0,0 +1,148 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// echoParam matches an echo path parameter; colons escaped with a backslash
+// are literal.
+var echoParam = regexp.MustCompile(`(^|[^\\]):(\w+)`)
+
+// openAPIPath converts an echo route path to its OpenAPI template.
+func openAPIPath(path string) string {
+	path = echoParam.ReplaceAllString(path, "$1{$2}")
+	return strings.ReplaceAll(path, `\:`, ":")
+}
+
+// routedApp returns a test app with every API route registered.
+func routedApp() *app {
+	a := testApp()
+	a.server = echo.New()
+	a.routes()
+	return a
+}
+
+func TestOpenAPISpec_Valid(t *testing.T) {
+	if _, err := loadOpenAPISpec(); err != nil {
+		t.Fatalf("loadOpenAPISpec() error: %v", err)
+	}
+}
+
+func TestOpenAPISpec_CoversRoutes(t *testing.T) {
+	doc, err := loadOpenAPISpec()
+	if err != nil {
+		t.Fatalf("loadOpenAPISpec() error: %v", err)
+	}
+	a := routedApp()
+
+	registered := make(map[string]bool)
+	for _, r := range a.server.Routes() {
+		path := openAPIPath(r.Path)
+		registered[r.Method+" "+path] = true
+		item := doc.Paths.Value(path)
+		if item == nil || item.GetOperation(r.Method) == nil {
+			t.Errorf("route %s %s is not described in openapi.json", r.Method, path)
+		}
+	}
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			if !registered[method+" "+path] {
+				t.Errorf("openapi.json describes %s %s, which is not registered", method, path)
+			}
+		}
+	}
+}
+
+func TestHandleGetOpenAPI(t *testing.T) {
+	a := testApp()
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/openapi.json", a)
+
+	err := a.HandleGetOpenAPI(c)
+	if err != nil {
+		t.Fatalf("HandleGetOpenAPI() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec.Body.Len() != len(openAPISpec) {
+		t.Errorf("body length = %d, want %d", rec.Body.Len(), len(openAPISpec))
+	}
+}
+
+func TestRequestValidation_RequirementsBody(t *testing.T) {
+	doc, err := loadOpenAPISpec()
+	if err != nil {
+		t.Fatalf("loadOpenAPISpec() error: %v", err)
+	}
+	validate, err := requestValidation(doc)
+	if err != nil {
+		t.Fatalf("requestValidation() error: %v", err)
+	}
+	a := routedApp()
+	a.server.Use(validate)
+
+	tests := []struct {
+		contentType string
+		body        string
+	}{
+		{mimeYAML, testProgram},
+		{echo.MIMEApplicationJSON, `{"name": "Minor", "requirements": [{"name": "Calculus", "from": ["MATH1013"]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/requirements:check?completed=MATH1013", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			rec := httptest.NewRecorder()
+			a.server.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+		})
+	}
+}
+
+func TestRequestValidation(t *testing.T) {
+	doc, err := loadOpenAPISpec()
+	if err != nil {
+		t.Fatalf("loadOpenAPISpec() error: %v", err)
+	}
+	validate, err := requestValidation(doc)
+	if err != nil {
+		t.Fatalf("requestValidation() error: %v", err)
+	}
+	e := echo.New()
+	e.Use(validate)
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusOK) }
+	e.GET("/v1/courses/:course", ok)
+	e.GET("/v1/courses/:course/offerings", ok)
+	e.GET("/v1/semesters/:semester", ok)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/v1/courses/COMP1021", http.StatusOK},
+		{"/v1/courses/comp%201021", http.StatusOK},
+		{"/v1/courses/COMP-1021/offerings", http.StatusOK},
+		{"/v1/courses/1234", http.StatusBadRequest},
+		{"/v1/semesters/current", http.StatusOK},
+		{"/v1/semesters/2510", http.StatusOK},
+		{"/v1/semesters/fall", http.StatusBadRequest},
+		{"/v1/unknown", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// refreshScheduleCalendar selects calendarSchedule as the refresh schedule.
+const refreshScheduleCalendar = "calendar"
+
+// newRefreshSchedule returns the schedule of full catalogue refreshes: every
+// RefreshInterval by default, a cron expression, or the calendar-aware
+// policy.
+func newRefreshSchedule(cfg config, cal *academicCalendar) (cron.Schedule, error) {
+	switch cfg.RefreshSchedule {
+	case "":
+		return cron.Every(cfg.RefreshInterval), nil
+	case refreshScheduleCalendar:
+		return calendarSchedule{
+			calendar:        cal,
+			addDropPeriod:   cfg.AddDropPeriod,
+			addDropInterval: cfg.AddDropRefreshInterval,
+			interval:        cfg.RefreshInterval,
+		}, nil
+	}
+	return cron.ParseStandard(cfg.RefreshSchedule)
+}
+
+// calendarSchedule refreshes often during the add/drop period at the start of
+// each semester, when enrolment figures change by the hour, and every
+// interval for the rest of the term.
+type calendarSchedule struct {
+	calendar        *academicCalendar
+	addDropPeriod   time.Duration
+	addDropInterval time.Duration
+	interval        time.Duration
+}
+
+func (s calendarSchedule) Next(t time.Time) time.Time {
+	start, next := s.calendar.semesterBoundsForTime(t)
+	if t.Before(start.Add(s.addDropPeriod)) {
+		return t.Add(s.addDropInterval)
+	}
+	// Do not sleep through the start of the next add/drop period.
+	return minTime(t.Add(s.interval), next)
+}
+
+func minTime(x, y time.Time) time.Time {
+	if y.Before(x) {
+		return y
+	}
+	return x
+}
+
+// precacheRetryMin is the wait after the first failed attempt of the initial
+// crawl, and precacheRetryMax bounds the wait between attempts of the initial crawl.
+const (
+	precacheRetryMin = 30 * time.Second
+	precacheRetryMax = rolloverRetryInterval
+)
+
+// precacheUntilReady crawls the current semester until a crawl succeeds or ctx
+// is cancelled, so that a transient upstream error at startup does not keep
+// the app unready until the next scheduled refresh. The wait between attempts
+// starts at retry and doubles up to precacheRetryMax.
+func (a *app) precacheUntilReady(ctx context.Context, retry time.Duration) {
+	for {
+		err := a.PreCacheCurrentSemesterCourses(ctx)
+		if err == nil {
+			return
+		}
+		a.logger.Error("Pre-caching failed", slog.String("error", err.Error()), slog.Duration("retry", retry))
+		timer := time.NewTimer(retry)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		retry = min(2*retry, precacheRetryMax)
+	}
+}
+
+// runRefreshLoop re-crawls the course catalogue whenever schedule fires, until
+// ctx is cancelled. The cached catalogue is served until the crawl replaces
+// it, and kept if the crawl fails.
+func (a *app) runRefreshLoop(ctx context.Context, schedule cron.Schedule) {
+	for {
+		next := schedule.Next(time.Now())
+		a.mu.Lock()
+		a.nextRefresh = next
+		a.mu.Unlock()
+		refreshNext.Set(float64(next.Unix()))
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		a.logger.Info("Refreshing course cache", slog.Time("scheduled", next))
+		if err := a.PreCacheCurrentSemesterCourses(ctx); err != nil {
+			a.logger.Error("Course cache refresh failed", slog.String("error", err.Error()))
+		}
+		if a.config.SemesterRange == "" {
+			if _, err := a.refreshSemesters(ctx); err != nil {
+				a.logger.Error("Semester discovery failed", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
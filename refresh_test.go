@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hkust-cse/crapi/courseinfopb"
+)
+
+func TestCalendarSchedule_Next(t *testing.T) {
+	s := calendarSchedule{
+		calendar:        defaultCalendar(),
+		addDropPeriod:   14 * 24 * time.Hour,
+		addDropInterval: time.Hour,
+		interval:        7 * 24 * time.Hour,
+	}
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "add/drop period of fall",
+			now:  time.Date(2025, 9, 3, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2025, 9, 3, 11, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "mid semester",
+			now:  time.Date(2025, 10, 1, 10, 0, 0, 0, time.UTC),
+			want: time.Date(2025, 10, 8, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "just before the next semester",
+			now:  time.Date(2026, 1, 28, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "end of the add/drop period",
+			now:  time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC),
+			want: time.Date(2026, 6, 22, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.Next(tt.now); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRefreshSchedule(t *testing.T) {
+	now := time.Date(2025, 10, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		schedule string
+		want     time.Time
+	}{
+		{"", now.Add(7 * 24 * time.Hour)},
+		{"0 * * * *", time.Date(2025, 10, 1, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)},
+		{refreshScheduleCalendar, now.Add(7 * 24 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.RefreshSchedule = tt.schedule
+			s, err := newRefreshSchedule(cfg, defaultCalendar())
+			if err != nil {
+				t.Fatalf("newRefreshSchedule() error: %v", err)
+			}
+			if got := s.Next(now); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreCacheCurrentSemesterCourses_ReplacesCatalogue(t *testing.T) {
+	srv := upstreamServer(t, "COMP")
+	a := testApp()
+	a.endpoint = srv.URL + "/2510"
+	a.remember(&CourseParsingResult{Code: "COMP9999", Course: &Course{Code: "COMP9999"}})
+
+	crawled, stopCrawled := a.watch()
+	defer stopCrawled()
+	if err := a.PreCacheCurrentSemesterCourses(context.Background()); err != nil {
+		t.Fatalf("PreCacheCurrentSemesterCourses() error: %v", err)
+	}
+	for _, want := range []courseChange{
+		{courseinfopb.CourseChange_TYPE_REMOVED, &Course{Code: "COMP9999"}},
+		{courseinfopb.CourseChange_TYPE_ADDED, a.cache["COMP1021"]},
+	} {
+		if got := <-crawled; got.Type != want.Type || got.Course.Code != want.Course.Code {
+			t.Errorf("change = %v %s, want %v %s", got.Type, got.Course.Code, want.Type, want.Course.Code)
+		}
+	}
+	if _, ok := a.cache["COMP9999"]; ok || a.cache["COMP1021"] == nil {
+		t.Fatalf("cache = %v, want only the crawled COMP1021", a.cache)
+	}
+	if _, ok := a.modified["COMP9999"]; ok {
+		t.Error("modification time of the dropped COMP9999 kept")
+	}
+	modified, lastModified := a.modified["COMP1021"], a.lastModified
+
+	// An unchanged catalogue keeps its validators and tells watchers nothing.
+	changes, stop := a.watch()
+	defer stop()
+	if err := a.PreCacheCurrentSemesterCourses(context.Background()); err != nil {
+		t.Fatalf("PreCacheCurrentSemesterCourses() error: %v", err)
+	}
+	if !a.modified["COMP1021"].Equal(modified) || !a.lastModified.Equal(lastModified) {
+		t.Errorf("modification times changed by an identical crawl")
+	}
+	if len(changes) != 0 {
+		t.Errorf("%d changes sent for an identical crawl, want none", len(changes))
+	}
+	if _, ok := a.cachedCourseLocked("ISOM1021"); !ok {
+		t.Error("ISOM1021 alias not rebuilt by the crawl")
+	}
+}
+
+func TestPreCacheCurrentSemesterCourses_KeepsCatalogueOnFailure(t *testing.T) {
+	srv := upstreamServer(t)
+	a := testApp()
+	a.endpoint = srv.URL + "/2510"
+	a.remember(&CourseParsingResult{Code: "COMP1021", Course: &Course{Code: "COMP1021", Title: "Cached"}})
+
+	if err := a.PreCacheCurrentSemesterCourses(context.Background()); err == nil {
+		t.Fatal("PreCacheCurrentSemesterCourses() succeeded without departments")
+	}
+	if course, ok := a.lookupCourse(context.Background(), "COMP1021", "COMP"); !ok || course.Title != "Cached" {
+		t.Errorf("COMP1021 = %v, want the cached course", course)
+	}
+	if got := a.searchCourses("cached", 10); len(got.Results) != 1 {
+		t.Errorf("search results = %+v, want the cached course", got.Results)
+	}
+}
+
+func TestPrecacheUntilReady(t *testing.T) {
+	var requests atomic.Int32
+	upstream := upstreamServer(t, "COMP").Config.Handler
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first two attempts fail on the semester index.
+		if requests.Add(1) <= 2 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		upstream.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	a := testApp()
+	a.endpoint = srv.URL + "/2510"
+
+	a.precacheUntilReady(context.Background(), time.Millisecond)
+	if !a.ready || a.cache["COMP1021"] == nil {
+		t.Errorf("ready = %v with %d courses, want ready with COMP1021", a.ready, len(a.cache))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a = testApp()
+	a.endpoint = upstreamServer(t).URL + "/2510"
+	a.precacheUntilReady(ctx, time.Hour)
+	if a.ready {
+		t.Error("ready after every attempt failed")
+	}
+}
+
+func TestPreCacheCurrentSemesterCourses_IncompleteCrawl(t *testing.T) {
+	// MATH is listed but its page is missing.
+	srv := upstreamServer(t, "COMP", "MATH")
+	store, err := openSnapshotStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := testApp()
+	a.endpoint = srv.URL + "/2510"
+	a.snapshots = store
+
+	if err := a.PreCacheCurrentSemesterCourses(context.Background()); !errors.Is(err, ErrIncompleteCrawl) {
+		t.Fatalf("PreCacheCurrentSemesterCourses() error = %v, want ErrIncompleteCrawl", err)
+	}
+	if a.ready || !a.lastRefresh.IsZero() || len(a.cache) != 0 {
+		t.Errorf("incomplete crawl used: ready = %v, lastRefresh = %v, %d courses", a.ready, a.lastRefresh, len(a.cache))
+	}
+	if found := store.find("COMP1021"); len(found) != 0 {
+		t.Errorf("snapshot saved from an incomplete crawl: %v", found)
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// program is a set of degree requirements, as maintained in a YAML, TOML or
+// JSON requirement definition file.
+type program struct {
+	Name         string        `yaml:"name" toml:"name" json:"name"`
+	Requirements []requirement `yaml:"requirements" toml:"requirements" json:"requirements"`
+}
+
+// requirement is met by completing Count of the courses matched by From.
+// Entries of From are course codes such as MATH1013, or patterns such as
+// COMP3xxx in which x stands for any digit.
+type requirement struct {
+	Name  string   `yaml:"name" toml:"name" json:"name"`
+	From  []string `yaml:"from" toml:"from" json:"from"`
+	Count int      `yaml:"count" toml:"count" json:"count"`
+
+	patterns []coursePattern
+}
+
+// coursePattern matches course codes by department and four-digit number,
+// either exactly or with wildcard digits. Wildcard patterns match any suffix.
+type coursePattern struct {
+	code     courseCode
+	wildcard bool
+}
+
+var coursePatternSyntax = regexp.MustCompile(`^([A-Z]+)([0-9X]{4})$`)
+
+func parseCoursePattern(raw string) (coursePattern, error) {
+	s := strings.ToUpper(strings.Join(strings.Fields(courseCodeSeparators.Replace(raw)), ""))
+	if m := coursePatternSyntax.FindStringSubmatch(s); m != nil && strings.Contains(m[2], "X") {
+		return coursePattern{code: courseCode{Department: m[1], Number: m[2]}, wildcard: true}, nil
+	}
+	code, err := parseCourseCode(raw)
+	if err != nil {
+		return coursePattern{}, fmt.Errorf("%q is neither a course code nor a pattern such as COMP3xxx", raw)
+	}
+	return coursePattern{code: code}, nil
+}
+
+func (p coursePattern) matches(code string) bool {
+	if !p.wildcard {
+		return p.code.String() == code
+	}
+	c, err := parseCourseCode(code)
+	if err != nil || c.Department != p.code.Department || len(c.Number) != len(p.code.Number) {
+		return false
+	}
+	for i := range len(c.Number) {
+		if p.code.Number[i] != 'X' && p.code.Number[i] != c.Number[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// matches reports whether course, under its own code or one it is
+// cross-listed with, counts towards r.
+func (r *requirement) matches(course *Course) bool {
+	return slices.ContainsFunc(r.patterns, func(p coursePattern) bool {
+		return p.matches(course.Code) || slices.ContainsFunc(course.CoListWith, p.matches)
+	})
+}
+
+// loadProgram reads a requirement definition file in YAML, TOML or JSON,
+// chosen by its extension.
+func loadProgram(name string) (*program, error) {
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		// Config files are never JSON, but definitions posted to the API
+		// may be; decodeProgram reads both YAML and JSON.
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		p, err := decodeProgram(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		return p, nil
+	}
+	var p program
+	if err := decodeConfigFile(name, &p); err != nil {
+		return nil, err
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &p, nil
+}
+
+// maxProgramSize is the largest requirement definition decodeProgram reads.
+const maxProgramSize = 64 << 10
+
+// decodeProgram reads a requirement definition in YAML or JSON from r.
+func decodeProgram(r io.Reader) (*program, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxProgramSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxProgramSize {
+		return nil, fmt.Errorf("%w: definition exceeds %d bytes", ErrInvalidRequirements, maxProgramSize)
+	}
+	var p program
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: empty definition", ErrInvalidRequirements)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRequirements, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// validate checks the program for consistency and parses its patterns.
+func (p *program) validate() error {
+	if len(p.Requirements) == 0 {
+		return fmt.Errorf("%w: no requirements defined", ErrInvalidRequirements)
+	}
+	for i := range p.Requirements {
+		r := &p.Requirements[i]
+		if r.Name == "" {
+			return fmt.Errorf("%w: requirement %d has no name", ErrInvalidRequirements, i+1)
+		}
+		if len(r.From) == 0 {
+			return fmt.Errorf("%w: requirement %q lists no courses", ErrInvalidRequirements, r.Name)
+		}
+		if r.Count < 0 {
+			return fmt.Errorf("%w: requirement %q count must not be negative", ErrInvalidRequirements, r.Name)
+		}
+		if r.Count == 0 {
+			r.Count = 1
+		}
+		r.patterns = nil
+		for _, raw := range r.From {
+			pattern, err := parseCoursePattern(raw)
+			if err != nil {
+				return fmt.Errorf("%w: requirement %q: %w", ErrInvalidRequirements, r.Name, err)
+			}
+			r.patterns = append(r.patterns, pattern)
+		}
+		if !slices.ContainsFunc(r.patterns, func(p coursePattern) bool { return p.wildcard }) && r.Count > len(r.patterns) {
+			return fmt.Errorf("%w: requirement %q needs %d of only %d courses", ErrInvalidRequirements, r.Name, r.Count, len(r.patterns))
+		}
+	}
+	return nil
+}
+
+// excludes reports whether credit for x and y cannot both count, according
+// to the exclusions published for either course.
+func excludes(x, y *Course) bool {
+	return slices.Contains(x.Exclusions, y.Code) || slices.Contains(y.Exclusions, x.Code)
+}
+
+// assignCourses assigns courses to the requirements, each course to at most
+// one requirement and each requirement at most its Count courses, so that as
+// many requirements as possible are filled. It returns the codes assigned to
+// each requirement, in the order of courses.
+//
+// Each requirement is a set of slots, and the assignment a maximum matching
+// of slots to courses, found with augmenting paths.
+func assignCourses(requirements []requirement, courses []*Course) [][]string {
+	// A requirement never takes more courses than there are, however large
+	// its count.
+	var slots []int
+	for i, r := range requirements {
+		for range min(r.Count, len(courses)) {
+			slots = append(slots, i)
+		}
+	}
+	owner := make([]int, len(courses))
+	for i := range owner {
+		owner[i] = -1
+	}
+	var augment func(slot int, seen []bool) bool
+	augment = func(slot int, seen []bool) bool {
+		r := &requirements[slots[slot]]
+		for i, course := range courses {
+			if seen[i] || !r.matches(course) {
+				continue
+			}
+			seen[i] = true
+			if owner[i] < 0 || augment(owner[i], seen) {
+				owner[i] = slot
+				return true
+			}
+		}
+		return false
+	}
+	for slot := range slots {
+		augment(slot, make([]bool, len(courses)))
+	}
+
+	assigned := make([][]string, len(requirements))
+	for i, slot := range owner {
+		if slot >= 0 {
+			assigned[slots[slot]] = append(assigned[slots[slot]], courses[i].Code)
+		}
+	}
+	return assigned
+}
+
+// checkRequirements reports how far the completed courses go towards the
+// program, and which cached courses of the current term could satisfy the
+// requirements left. A completed course counts towards one requirement only,
+// chosen to meet as many requirements as possible, and not at all if an
+// earlier completed course excludes it.
+func (a *app) checkRequirements(p *program, completed []string) requirementsCheck {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	// Courses not offered this term are known by code only, without
+	// exclusions.
+	known := func(code string) *Course {
+		if course, ok := a.cachedCourseLocked(code); ok {
+			return course
+		}
+		return &Course{Code: code}
+	}
+	check := requirementsCheck{Program: p.Name, Met: true, Conflicts: []exclusionConflict{}}
+	var counted []*Course
+	done := make(map[string]bool)
+	for _, code := range completed {
+		course := known(code)
+		done[course.Code] = true
+		if i := slices.IndexFunc(counted, func(c *Course) bool { return excludes(c, course) }); i >= 0 {
+			check.Conflicts = append(check.Conflicts, exclusionConflict{Course: code, ExcludedBy: counted[i].Code})
+			continue
+		}
+		counted = append(counted, course)
+	}
+
+	assigned := assignCourses(p.Requirements, counted)
+	for i, r := range p.Requirements {
+		result := requirementResult{Name: r.Name, Required: r.Count, SatisfiedBy: []string{}, Options: []string{}}
+		result.SatisfiedBy = append(result.SatisfiedBy, assigned[i]...)
+		result.Met = len(result.SatisfiedBy) >= r.Count
+		if !result.Met {
+			check.Met = false
+			for code, course := range a.cache {
+				if done[code] || !r.matches(course) {
+					continue
+				}
+				if slices.ContainsFunc(counted, func(c *Course) bool { return excludes(c, course) }) {
+					continue
+				}
+				result.Options = append(result.Options, code)
+			}
+			slices.Sort(result.Options)
+		}
+		check.Requirements = append(check.Requirements, result)
+	}
+	return check
+}
+
+func (a *app) HandleCheckRequirements(c echo.Context) error {
+	a.logger.Info("POST /v1/requirements:check")
+	var completed []string
+	for raw := range strings.SplitSeq(c.QueryParam("completed"), ",") {
+		if raw = strings.TrimSpace(raw); raw == "" {
+			continue
+		}
+		code, _, err := normalizeCourseCode(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse{
+				Status:  "error",
+				Message: fmt.Sprintf("%q: %s", raw, err),
+			})
+			return nil
+		}
+		if !slices.Contains(completed, code) {
+			completed = append(completed, code)
+		}
+	}
+	p, err := decodeProgram(c.Request().Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	c.JSON(http.StatusOK, a.checkRequirements(p, completed))
+	return nil
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+const testProgram = `
+name: BEng Computer Science
+requirements:
+  - name: Introductory programming
+    from: [COMP1021, COMP1022P]
+  - name: Calculus
+    from: [MATH1013, MATH1023]
+  - name: Advanced computer science
+    from: [COMP3xxx]
+    count: 2
+`
+
+func TestCoursePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		code    string
+		want    bool
+	}{
+		{"COMP3xxx", "COMP3111", true},
+		{"COMP3xxx", "COMP3111H", true},
+		{"comp 3xxx", "COMP3711", true},
+		{"COMP3xxx", "COMP4211", false},
+		{"COMP3xxx", "MATH3111", false},
+		{"COMP39xx", "COMP3911", true},
+		{"MATH1013", "MATH1013", true},
+		{"MATH1013", "MATH1023", false},
+		{"COMP4901X", "COMP4901X", true},
+		{"COMP4901X", "COMP4901", false},
+	}
+	for _, tt := range tests {
+		p, err := parseCoursePattern(tt.pattern)
+		if err != nil {
+			t.Fatalf("parseCoursePattern(%q) error: %v", tt.pattern, err)
+		}
+		if got := p.matches(tt.code); got != tt.want {
+			t.Errorf("%s matches %s = %v, want %v", tt.pattern, tt.code, got, tt.want)
+		}
+	}
+	if _, err := parseCoursePattern("3xxx"); err == nil {
+		t.Error("parseCoursePattern(3xxx) succeeded, want error")
+	}
+}
+
+func TestLoadProgram(t *testing.T) {
+	p, err := loadProgram(writeConfigFile(t, "program.yaml", testProgram))
+	if err != nil {
+		t.Fatalf("loadProgram() error: %v", err)
+	}
+	if p.Name != "BEng Computer Science" || len(p.Requirements) != 3 {
+		t.Fatalf("loadProgram() = %+v", p)
+	}
+	if r := p.Requirements[0]; r.Count != 1 || len(r.patterns) != 2 {
+		t.Errorf("requirement %q count = %d with %d patterns, want 1 with 2", r.Name, r.Count, len(r.patterns))
+	}
+
+	toml := `
+name = "Minor"
+[[requirements]]
+name = "Calculus"
+from = ["MATH1013"]
+`
+	if _, err := loadProgram(writeConfigFile(t, "minor.toml", toml)); err != nil {
+		t.Errorf("loadProgram(toml) error: %v", err)
+	}
+
+	definition := `{"name": "Minor", "requirements": [{"name": "Calculus", "from": ["MATH1013"]}]}`
+	if p, err := loadProgram(writeConfigFile(t, "minor.json", definition)); err != nil || p.Requirements[0].Name != "Calculus" {
+		t.Errorf("loadProgram(json) = %+v, %v", p, err)
+	}
+	if _, err := loadProgram(writeConfigFile(t, "empty.json", "{}")); !errors.Is(err, ErrInvalidRequirements) {
+		t.Errorf("loadProgram(empty json) error = %v, want ErrInvalidRequirements", err)
+	}
+}
+
+func TestLoadProgram_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no requirements": "name: Empty\n",
+		"no name":         "requirements:\n  - from: [COMP1021]\n",
+		"no courses":      "requirements:\n  - name: Nothing\n",
+		"bad pattern":     "requirements:\n  - name: Bad\n    from: [COMP]\n",
+		"count too high":  "requirements:\n  - name: Both\n    from: [MATH1013, MATH1023]\n    count: 3\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadProgram(writeConfigFile(t, "program.yaml", content))
+			if !errors.Is(err, ErrInvalidRequirements) {
+				t.Errorf("loadProgram() error = %v, want ErrInvalidRequirements", err)
+			}
+		})
+	}
+}
+
+func requirementsTestApp() *app {
+	return fixtureApp(
+		&Course{Code: "COMP3111"},
+		&Course{Code: "COMP3311"},
+		&Course{Code: "COMP3711", Exclusions: []string{"COMP3711H"}},
+		&Course{Code: "COMP3711H"},
+		&Course{Code: "MATH1023"},
+	)
+}
+
+func TestCheckRequirements(t *testing.T) {
+	a := requirementsTestApp()
+	p, err := decodeProgram(strings.NewReader(testProgram))
+	if err != nil {
+		t.Fatalf("decodeProgram() error: %v", err)
+	}
+
+	got := a.checkRequirements(p, []string{"COMP1021", "COMP1022P", "COMP3711H"})
+	if got.Met {
+		t.Error("Met = true, want false")
+	}
+	want := []exclusionConflict{{Course: "COMP1022P", ExcludedBy: "COMP1021"}}
+	if !slices.Equal(got.Conflicts, want) {
+		t.Errorf("Conflicts = %+v, want %+v", got.Conflicts, want)
+	}
+	intro, calculus, advanced := got.Requirements[0], got.Requirements[1], got.Requirements[2]
+	if !intro.Met || !slices.Equal(intro.SatisfiedBy, []string{"COMP1021"}) || len(intro.Options) != 0 {
+		t.Errorf("introductory programming = %+v", intro)
+	}
+	if calculus.Met || !slices.Equal(calculus.Options, []string{"MATH1013", "MATH1023"}) {
+		t.Errorf("calculus = %+v", calculus)
+	}
+	// COMP3711 is excluded by the completed COMP3711H.
+	if advanced.Met || !slices.Equal(advanced.SatisfiedBy, []string{"COMP3711H"}) ||
+		!slices.Equal(advanced.Options, []string{"COMP3111", "COMP3311"}) {
+		t.Errorf("advanced computer science = %+v", advanced)
+	}
+
+	got = a.checkRequirements(p, []string{"COMP1022P", "MATH1023", "COMP3111", "COMP3311"})
+	if !got.Met {
+		t.Errorf("Met = false, want true: %+v", got)
+	}
+}
+
+func TestCheckRequirements_Assignment(t *testing.T) {
+	a := requirementsTestApp()
+	// Taking COMP3111 for the broad requirement listed first would leave the
+	// specific one unmet.
+	p, err := decodeProgram(strings.NewReader(`
+name: BSc Computer Science
+requirements:
+  - name: Advanced computer science
+    from: [COMP3xxx]
+    count: 2
+  - name: Software engineering
+    from: [COMP3111]
+`))
+	if err != nil {
+		t.Fatalf("decodeProgram() error: %v", err)
+	}
+	got := a.checkRequirements(p, []string{"COMP3111", "COMP3311", "COMP3511"})
+	if !got.Met {
+		t.Errorf("Met = false, want true: %+v", got)
+	}
+	if advanced := got.Requirements[0].SatisfiedBy; !slices.Equal(advanced, []string{"COMP3311", "COMP3511"}) {
+		t.Errorf("advanced computer science satisfied by %v, want [COMP3311 COMP3511]", advanced)
+	}
+	if software := got.Requirements[1].SatisfiedBy; !slices.Equal(software, []string{"COMP3111"}) {
+		t.Errorf("software engineering satisfied by %v, want [COMP3111]", software)
+	}
+}
+
+func TestHandleCheckRequirements(t *testing.T) {
+	a := requirementsTestApp()
+	req := httptest.NewRequest(http.MethodPost, "/v1/requirements:check?completed=comp1021,MATH-1013", strings.NewReader(testProgram))
+	req.Header.Set("Content-Type", "application/yaml")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	if err := a.HandleCheckRequirements(c); err != nil {
+		t.Fatalf("HandleCheckRequirements() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var got requirementsCheck
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if got.Program != "BEng Computer Science" || len(got.Requirements) != 3 || !got.Requirements[1].Met {
+		t.Errorf("response = %+v", got)
+	}
+
+	c, rec = setupHandlerTest(http.MethodPost, "/v1/requirements:check", a)
+	if err := a.HandleCheckRequirements(c); err != nil {
+		t.Fatalf("HandleCheckRequirements() error: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("empty body status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestDecodeProgram_TooLarge(t *testing.T) {
+	definition := testProgram + "# " + strings.Repeat("x", maxProgramSize) + "\n"
+	if _, err := decodeProgram(strings.NewReader(definition)); !errors.Is(err, ErrInvalidRequirements) {
+		t.Errorf("decodeProgram() error = %v, want ErrInvalidRequirements", err)
+	}
+}
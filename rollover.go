@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// rolloverRetryInterval is how long to wait before crawling the new semester
+// again after a failed rollover.
+const rolloverRetryInterval = 15 * time.Minute
+
+// archivedTerm holds the courses of the semester served before the last
+// rollover, so that clients can still read it while the new term is crawled
+// and after.
+type archivedTerm struct {
+	semester     string
+	courses      map[string]*Course
+	departments  []department
+	lastModified time.Time
+}
+
+// sortedCourses returns the archived courses sorted by code.
+func (t *archivedTerm) sortedCourses() []*Course {
+	courses := make([]*Course, 0, len(t.courses))
+	for _, course := range t.courses {
+		courses = append(courses, course)
+	}
+	slices.SortFunc(courses, func(x, y *Course) int {
+		return strings.Compare(x.Code, y.Code)
+	})
+	return courses
+}
+
+// course returns the archived course with the given code, or the one it was
+// cross-listed under, as cachedCourseLocked does for the current term.
+func (t *archivedTerm) course(code string) (*Course, bool) {
+	if course, ok := t.courses[code]; ok {
+		return course, true
+	}
+	for _, course := range t.sortedCourses() {
+		if slices.Contains(course.CoListWith, code) {
+			return course, true
+		}
+	}
+	return nil, false
+}
+
+// switchSemesterLocked archives the current term and points the app at the
+// semester at endpoint with an empty cache, returning the code of the
+// archived term. a.mu must be held.
+func (a *app) switchSemesterLocked(endpoint string) string {
+	previous := path.Base(a.endpoint)
+	a.previous = &archivedTerm{
+		semester:     previous,
+		courses:      a.cache,
+		departments:  a.departmentCache,
+		lastModified: a.lastModified,
+	}
+	a.endpoint = endpoint
+	a.clearCoursesLocked()
+	a.departmentCache = []department{}
+	a.semesters = nil
+	a.lastModified = time.Now()
+	return previous
+}
+
+// checkRollover crawls the current semester and switches to it if it differs
+// from the one being served, reporting whether a rollover happened. The old
+// term is served until the new one has been crawled, and kept if the crawl
+// fails, to be retried at the next check.
+func (a *app) checkRollover(ctx context.Context) (bool, error) {
+	current, err := a.calendar.currentSemesterCode()
+	if err != nil {
+		return false, err
+	}
+	if current == a.semester() {
+		return false, nil
+	}
+	if err := a.precacheSemester(ctx, fmt.Sprintf("%s/%s", a.config.BaseURL, current), true); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// runRolloverLoop checks for a new semester whenever a term boundary passes
+// in Hong Kong, until ctx is cancelled. A failed rollover is retried every
+// rolloverRetryInterval.
+func (a *app) runRolloverLoop(ctx context.Context) {
+	var retry bool
+	for {
+		_, next := a.calendar.semesterBoundsForTime(time.Now().In(campusTime))
+		if retry {
+			next = time.Now().Add(rolloverRetryInterval)
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		_, err := a.checkRollover(ctx)
+		if retry = err != nil; retry {
+			a.logger.Error("Semester rollover failed", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// requestedTerm returns the archived term selected by the semester query
+// parameter, or nil when the current term is requested. Terms older than the
+// previous one are read from the database, if configured.
+func (a *app) requestedTerm(c echo.Context) (*archivedTerm, error) {
+	semester := c.QueryParam("semester")
+	if semester == "" || semester == a.semester() {
+		return nil, nil
+	}
+	a.mu.RLock()
+	previous := a.previous
+	a.mu.RUnlock()
+	if previous != nil && previous.semester == semester {
+		return previous, nil
+	}
+	if a.store != nil {
+		return a.store.loadTerm(c.Request().Context(), semester)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSemesterNotAvailable, semester)
+}
+
+// requestedTermStatus is the HTTP status for an error from requestedTerm.
+func requestedTermStatus(err error) int {
+	if errors.Is(err, ErrSemesterNotAvailable) {
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hkust-cse/crapi/courseinfopb"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// rollOver switches a to semester with an empty catalogue.
+func rollOver(a *app, semester string) {
+	a.replaceCourses(&crawl{
+		endpoint:    fmt.Sprintf("%s/%s", a.config.BaseURL, semester),
+		rollover:    true,
+		departments: []department{},
+		courses:     map[string]*Course{},
+		crawledAt:   time.Now(),
+	})
+}
+
+func TestReplaceCourses_Rollover(t *testing.T) {
+	a := testApp()
+	a.config.BaseURL = "http://127.0.0.1:1"
+	a.endpoint = "http://127.0.0.1:1/2540"
+	a.remember(&CourseParsingResult{Code: "COMP1021", Course: &Course{Code: "COMP1021", Title: "Summer COMP1021"}})
+	a.departmentCache = []department{{Code: "COMP"}}
+
+	before := testutil.ToFloat64(semesterRollovers)
+	changes, stop := a.watch()
+	defer stop()
+	a.replaceCourses(&crawl{
+		endpoint:    "http://127.0.0.1:1/2610",
+		rollover:    true,
+		departments: []department{{Code: "MATH"}},
+		courses:     map[string]*Course{"MATH1013": {Code: "MATH1013"}},
+		crawledAt:   time.Now(),
+	})
+
+	if got := a.semester(); got != "2610" {
+		t.Errorf("semester() = %q, want %q", got, "2610")
+	}
+	if len(a.cache) != 1 || a.cache["MATH1013"] == nil || len(a.modified) != 1 {
+		t.Errorf("new term cache = %v with %d modification times, want only MATH1013", a.cache, len(a.modified))
+	}
+	if len(a.departmentCache) != 1 || a.departmentCache[0].Code != "MATH" {
+		t.Errorf("departments = %+v, want MATH", a.departmentCache)
+	}
+	if a.previous == nil || a.previous.semester != "2540" || a.previous.courses["COMP1021"] == nil {
+		t.Fatalf("previous term = %+v, want 2540 with COMP1021", a.previous)
+	}
+	if got := testutil.ToFloat64(semesterRollovers) - before; got != 1 {
+		t.Errorf("rollovers recorded = %v, want 1", got)
+	}
+	// Watchers see the old term's courses go and the new term's arrive.
+	for _, want := range []courseinfopb.CourseChange_Type{courseinfopb.CourseChange_TYPE_REMOVED, courseinfopb.CourseChange_TYPE_ADDED} {
+		if got := <-changes; got.Type != want {
+			t.Errorf("change = %v %s, want %v", got.Type, got.Course.Code, want)
+		}
+	}
+}
+
+func TestReplaceCourses_OutOfOrder(t *testing.T) {
+	a := testApp()
+	a.endpoint = "http://127.0.0.1:1/2540"
+	a.remember(&CourseParsingResult{Code: "COMP1021", Course: &Course{Code: "COMP1021", Title: "Summer COMP1021"}})
+
+	// The rollover crawl of 2610 finishes before a refresh of 2540 started
+	// earlier.
+	rolled := a.replaceCourses(&crawl{
+		endpoint:  "http://127.0.0.1:1/2610",
+		rollover:  true,
+		courses:   map[string]*Course{"MATH1013": {Code: "MATH1013"}},
+		crawledAt: time.Now(),
+	})
+	stale := a.replaceCourses(&crawl{
+		endpoint:  "http://127.0.0.1:1/2540",
+		courses:   map[string]*Course{"COMP1021": {Code: "COMP1021", Title: "Summer COMP1021"}},
+		crawledAt: time.Now(),
+	})
+
+	if !rolled || stale {
+		t.Errorf("replaceCourses() = %v, %v, want the rollover applied and the stale crawl discarded", rolled, stale)
+	}
+	if got := a.semester(); got != "2610" || a.cache["MATH1013"] == nil || a.cache["COMP1021"] != nil {
+		t.Errorf("serving %s with %v, want 2610 with MATH1013", got, a.cache)
+	}
+	if a.previous == nil || a.previous.semester != "2540" {
+		t.Errorf("previous term = %+v, want 2540", a.previous)
+	}
+}
+
+func TestCheckRollover(t *testing.T) {
+	current, err := defaultCalendar().currentSemesterCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name        string
+		departments []string
+		rolled      bool
+	}{
+		{"crawl fails", nil, false},
+		{"crawl succeeds", []string{"COMP"}, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			a := testApp()
+			a.config.BaseURL = upstreamServer(t, tt.departments...).URL
+			a.endpoint = a.config.BaseURL + "/1910"
+			a.remember(&CourseParsingResult{Code: "COMP1021", Course: &Course{Code: "COMP1021", Title: "Old"}})
+
+			rolled, err := a.checkRollover(context.Background())
+			if rolled != tt.rolled || (err == nil) != tt.rolled {
+				t.Fatalf("checkRollover() = %v, %v, want rolled over %v", rolled, err, tt.rolled)
+			}
+			want, title := "1910", "Old"
+			if tt.rolled {
+				want, title = current, "Introduction to Computer Science"
+			}
+			course, ok := a.lookupCourse(context.Background(), "COMP1021", "COMP")
+			if a.semester() != want || !ok || course.Title != title {
+				t.Errorf("serving %s with COMP1021 %v, want %s with %q", a.semester(), course, want, title)
+			}
+		})
+	}
+}
+
+func TestHandleGetCourse_PreviousSemester(t *testing.T) {
+	a := testApp()
+	a.endpoint = "http://127.0.0.1:1/2540"
+	a.cache["COMP1021"] = &Course{Code: "COMP1021", Title: "Summer COMP1021"}
+	rollOver(a, "2610")
+	a.cache["COMP1021"] = &Course{Code: "COMP1021", Title: "Fall COMP1021"}
+
+	tests := []struct {
+		semester string
+		status   int
+		title    string
+	}{
+		{"", http.StatusOK, "Fall COMP1021"},
+		{"2610", http.StatusOK, "Fall COMP1021"},
+		{"2540", http.StatusOK, "Summer COMP1021"},
+		{"2530", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.semester, func(t *testing.T) {
+			c, rec := setupHandlerTest(http.MethodGet, "/v1/courses/COMP1021?semester="+tt.semester, a)
+			c.SetParamNames("course")
+			c.SetParamValues("COMP1021")
+			if err := a.HandleGetCourse(c); err != nil {
+				t.Fatalf("HandleGetCourse() error: %v", err)
+			}
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.title == "" {
+				return
+			}
+			var got Course
+			if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Unmarshal error: %v", err)
+			}
+			if got.Title != tt.title {
+				t.Errorf("Title = %q, want %q", got.Title, tt.title)
+			}
+		})
+	}
+}
+
+func TestHandleGetCourses_PreviousSemester(t *testing.T) {
+	a := testApp()
+	a.endpoint = "http://127.0.0.1:1/2540"
+	a.cache["COMP1021"] = &Course{Code: "COMP1021"}
+	a.cache["MATH1013"] = &Course{Code: "MATH1013"}
+	rollOver(a, "2610")
+
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/courses?semester=2540", a)
+	if err := a.HandleGetCourses(c); err != nil {
+		t.Fatalf("HandleGetCourses() error: %v", err)
+	}
+	var got []Course
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if len(got) != 2 || got[0].Code != "COMP1021" || got[1].Code != "MATH1013" {
+		t.Errorf("courses = %+v, want COMP1021 and MATH1013", got)
+	}
+}
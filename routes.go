@@ -9,13 +9,24 @@ import (
 func (a *app) routes() {
 	a.logger.Info("Setting up route handlers")
 	a.server.GET("/healthz", a.HandleHealthCheck)
+	a.server.GET("/readyz", a.HandleReadinessCheck)
 	a.server.GET("/", func(c echo.Context) error {
 		return c.Redirect(http.StatusMovedPermanently, "/v1")
 	})
 	group := a.server.Group("/v1")
 	group.GET("", a.HandleIntrospection)
+	group.GET("/openapi.json", a.HandleGetOpenAPI)
+	group.GET("/semesters", a.HandleListSemesters)
 	group.GET("/semesters/:semester", a.HandleGetSemester)
+	group.GET("/departments", a.HandleGetDepartments)
 	group.GET("/courses/:course", a.HandleGetCourse)
+	group.GET("/courses/:course/offerings", a.HandleGetCourseOfferings)
 	group.GET("/courses", a.HandleGetCourses)
 	group.PATCH("/courses", a.HandleRefreshCourses)
+	group.POST("/courses\\:batchGet", a.HandleBatchGetCourses)
+	group.GET("/search", a.HandleSearchCourses)
+	group.GET("/suggest", a.HandleSuggestCourses)
+	group.GET("/compare", a.HandleCompareCourses)
+	group.POST("/requirements\\:check", a.HandleCheckRequirements)
+	group.POST("/graphql", a.graphQLHandler())
 }
@@ -1,19 +1,33 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
-	"slices"
+	"net/http"
+	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gocolly/colly/v2"
+	"go.opentelemetry.io/otel/trace"
 )
 
+const upstreamProbeTimeout = 2 * time.Second
+
+// upstreamProbeInterval is how often the upstream is probed for the readiness
+// check.
+const upstreamProbeInterval = 30 * time.Second
+
 func ParseCourse(e *colly.HTMLElement, logger *slog.Logger) (*CourseParsingResult, error) {
 	courseCode, courseTitle, _ := strings.Cut(e.ChildText("div.courseinfo > div.courseattrContainer > div.subject"), " - ")
 	logger.Info("Parsing for", "courseCode", courseCode)
-	code := strings.ReplaceAll(courseCode, " ", "")
+	parsedCode, err := parseCourseCode(courseCode)
+	if err != nil {
+		return nil, fmt.Errorf("course parsing: %q: %w", courseCode, err)
+	}
+	code := parsedCode.String()
 
 	openParen := strings.LastIndex(courseTitle, "(")
 	closeParen := strings.LastIndex(courseTitle, ")")
@@ -37,6 +51,19 @@ func ParseCourse(e *colly.HTMLElement, logger *slog.Logger) (*CourseParsingResul
 		Credits:     unit,
 		Instructors: make(map[string][]string),
 	}
+	attributes := courseAttributes(e)
+	course.Description = attributes["DESCRIPTION"]
+	course.Prerequisites = attributes["PRE-REQUISITE"]
+	for _, excluded := range mentionedCourseCodes(attributes["EXCLUSION"]) {
+		if excluded != code {
+			course.Exclusions = append(course.Exclusions, excluded)
+		}
+	}
+	for _, alias := range mentionedCourseCodes(attributes["CO-LIST WITH"]) {
+		if alias != code {
+			course.CoListWith = append(course.CoListWith, alias)
+		}
+	}
 	e.ForEach(".newsect", func(i int, e *colly.HTMLElement) {
 		var sectionCode string
 		for _, section := range e.ChildTexts("td:nth-child(1)") {
@@ -49,6 +76,15 @@ func ParseCourse(e *colly.HTMLElement, logger *slog.Logger) (*CourseParsingResul
 			}
 		}
 		course.Sections = append(course.Sections, sectionCode)
+		course.Schedule = append(course.Schedule, Section{
+			Code:  sectionCode,
+			Time:  e.ChildText("td:nth-child(2)"),
+			Room:  e.ChildText("td:nth-child(3)"),
+			Quota: parseCount(e.ChildText("td:nth-child(6)")),
+			Enrol: parseCount(e.ChildText("td:nth-child(7)")),
+			Avail: parseCount(e.ChildText("td:nth-child(8)")),
+			Wait:  parseCount(e.ChildText("td:nth-child(9)")),
+		})
 		taTexts := e.ChildTexts("td:nth-child(5) > div.taListContainer > div.taList > a")
 		isTutorial := len(taTexts) > 0 && taTexts[0] != ""
 		querySelector := "td:nth-child(4) > div.instructorList > a"
@@ -65,57 +101,214 @@ func ParseCourse(e *colly.HTMLElement, logger *slog.Logger) (*CourseParsingResul
 	}, nil
 }
 
-func (a *app) GetCourse(department string) {
-	collector := colly.NewCollector()
-	collector.OnHTML("div[class=course]", func(e *colly.HTMLElement) {
-		result, err := ParseCourse(e, a.logger)
-		if err != nil {
-			a.logger.Error("error while parsing course", slog.String("error", err.Error()))
-			return
+// courseAttributes returns the rows of a course's attribute popup, such as
+// its description and co-listings, keyed by their upper-case heading.
+func courseAttributes(e *colly.HTMLElement) map[string]string {
+	attributes := make(map[string]string)
+	e.ForEach("div.courseattr div.popupdetail tr", func(_ int, row *colly.HTMLElement) {
+		heading := strings.ToUpper(strings.TrimSpace(row.ChildText("th")))
+		if heading != "" {
+			attributes[heading] = strings.TrimSpace(row.ChildText("td"))
 		}
-		a.remember(result)
 	})
-	collector.Visit(fmt.Sprintf("%s/subject/%s", a.getEndpoint(), department))
+	return attributes
 }
 
-func (a *app) PreCacheCurrentSemesterCourses() {
-	collector := colly.NewCollector()
+// parseCount returns the number at the start of a quota or enrolment cell,
+// ignoring any reserved-quota annotations that follow it.
+func parseCount(s string) int {
+	s = strings.TrimSpace(s)
+	end := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if end >= 0 {
+		s = s[:end]
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// newCollector returns a colly collector that records fetched pages and
+// passes every course block parsed from them to found. Upstream requests and
+// parsing are traced as children of ctx.
+func (a *app) newCollector(ctx context.Context, found func(*CourseParsingResult)) *colly.Collector {
+	collector := colly.NewCollector(colly.StdlibContext(ctx))
+	collector.WithTransport(tracingTransport{base: http.DefaultTransport})
+	collector.OnResponse(func(r *colly.Response) {
+		pagesFetched.WithLabelValues(strconv.Itoa(r.StatusCode)).Inc()
+	})
+	collector.OnError(func(r *colly.Response, err error) {
+		pagesFetched.WithLabelValues(strconv.Itoa(r.StatusCode)).Inc()
+	})
 	collector.OnHTML("div[class=course]", func(e *colly.HTMLElement) {
+		_, span := startSpan(ctx, "ParseCourse")
+		defer span.End()
 		result, err := ParseCourse(e, a.logger)
 		if err != nil {
+			parseFailures.Inc()
+			spanError(span, err)
 			a.logger.Error("error while parsing course", slog.String("error", err.Error()))
 			return
 		}
-		a.remember(result)
+		span.SetAttributes(attrCourseCode.String(result.Code))
+		found(result)
 	})
-	collector.OnHTML("a[class=ug]", func(e *colly.HTMLElement) {
-		department := e.Text
-		a.mu.RLock()
-		found := slices.Contains(a.departmentCache, department)
-		a.mu.RUnlock()
-		if !found {
-			a.logger.Info("Traversing courses for", "department", department)
-			a.mu.Lock()
-			a.departmentCache = append(a.departmentCache, department)
-			a.mu.Unlock()
-			collector.Visit(fmt.Sprintf("%s/subject/%s", a.getEndpoint(), department))
-		}
+	return collector
+}
+
+// visitDepartment scrapes a single department's subject page of the semester
+// at endpoint.
+func (a *app) visitDepartment(collector *colly.Collector, endpoint, department string) error {
+	start := time.Now()
+	defer func() {
+		scrapeDuration.WithLabelValues(department).Observe(time.Since(start).Seconds())
+	}()
+	return collector.Visit(fmt.Sprintf("%s/subject/%s", endpoint, department))
+}
+
+func (a *app) GetCourse(ctx context.Context, department string) {
+	if a.offline() {
+		return
+	}
+	ctx, span := startSpan(ctx, "GetCourse", trace.WithAttributes(attrDepartment.String(department)))
+	defer span.End()
+	if err := a.visitDepartment(a.newCollector(ctx, a.remember), a.getEndpoint(), department); err != nil {
+		spanError(span, err)
+	}
+}
+
+// crawl is the catalogue of a semester scraped in full, before it replaces
+// the cached one.
+type crawl struct {
+	endpoint string
+	// rollover allows the crawl to switch the semester served to its own.
+	rollover    bool
+	departments []department
+	courses     map[string]*Course
+	crawledAt   time.Time
+}
+
+// crawlSemester scrapes every department of the semester at endpoint into a
+// new catalogue, leaving the cache alone so that clients are served the
+// previous catalogue until the crawl is complete. A crawl missing any
+// department fails with ErrIncompleteCrawl, so that it never replaces a
+// complete one.
+func (a *app) crawlSemester(ctx context.Context, endpoint string) (*crawl, error) {
+	// Department lists differ between terms, so only a crawl of the current
+	// term is checked against the departments it had.
+	previous := 0
+	a.mu.RLock()
+	if endpoint == a.endpoint {
+		previous = len(a.departmentCache)
+	}
+	a.mu.RUnlock()
+	departments, err := a.discoverDepartmentsAt(ctx, endpoint, previous)
+	if err != nil {
+		return nil, err
+	}
+	result := &crawl{endpoint: endpoint, departments: departments, courses: make(map[string]*Course)}
+	collector := a.newCollector(ctx, func(r *CourseParsingResult) {
+		result.courses[r.Code] = r.Course
 	})
-	collector.OnHTML("a[class=pg]", func(e *colly.HTMLElement) {
-		department := e.Text
-		a.mu.RLock()
-		found := slices.Contains(a.departmentCache, department)
-		a.mu.RUnlock()
-		if !found {
-			a.logger.Info("Traversing courses for", "department", department)
-			a.mu.Lock()
-			a.departmentCache = append(a.departmentCache, department)
-			a.mu.Unlock()
-			collector.Visit(fmt.Sprintf("%s/subject/%s", a.getEndpoint(), department))
+	var failed []string
+	for _, d := range departments {
+		a.logger.Info("Traversing courses for", "department", d.Code)
+		if err := a.visitDepartment(collector, endpoint, d.Code); err != nil {
+			a.logger.Error("error while visting page", slog.String("department", d.Code), slog.String("error", err.Error()))
+			failed = append(failed, d.Code)
 		}
-	})
-	err := collector.Visit(fmt.Sprintf("%s/subject/COMP", a.getEndpoint()))
+	}
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrIncompleteCrawl, strings.Join(failed, ", "))
+	}
+	result.crawledAt = time.Now()
+	return result, nil
+}
+
+func (a *app) PreCacheCurrentSemesterCourses(ctx context.Context) error {
+	return a.precacheSemester(ctx, a.getEndpoint(), false)
+}
+
+// precacheSemester crawls the semester at endpoint and serves it once the
+// crawl succeeds. With rollover, the app switches to that semester if it is
+// not the one served; otherwise a crawl of a semester no longer served, such
+// as one overtaken by a rollover, fails with ErrSemesterChanged. The current
+// catalogue is kept if the crawl fails.
+func (a *app) precacheSemester(ctx context.Context, endpoint string, rollover bool) error {
+	if a.offline() {
+		return ErrReadOnlyDataset
+	}
+	ctx, span := startSpan(ctx, "PreCacheCurrentSemesterCourses")
+	defer span.End()
+	start := time.Now()
+	defer func() {
+		refreshDuration.Observe(time.Since(start).Seconds())
+	}()
+	result, err := a.crawlSemester(ctx, endpoint)
+	if err != nil {
+		spanError(span, err)
+		a.logger.Error("error while crawling semester", slog.String("error", err.Error()))
+		return err
+	}
+	result.rollover = rollover
+	now := result.crawledAt
+	if !a.replaceCourses(result) {
+		err := fmt.Errorf("%w: %s is no longer served", ErrSemesterChanged, path.Base(endpoint))
+		a.logger.Warn("Discarding crawl", slog.String("error", err.Error()))
+		return err
+	}
+	a.markReady(now)
+	refreshLastSuccess.Set(float64(now.Unix()))
+	// Persist the crawl under the semester crawled, whatever is served by
+	// the time the writes run.
+	semester := path.Base(result.endpoint)
+	if err := a.saveSnapshot(semester, result); err != nil {
+		a.logger.Error("error while saving snapshot", slog.String("error", err.Error()))
+	}
+	if err := a.storeSemester(ctx, semester, result); err != nil {
+		a.logger.Error("error while storing semester", slog.String("error", err.Error()))
+	}
+	return nil
+}
+
+// probeUpstream records whether the upstream is reachable for the readiness
+// check.
+func (a *app) probeUpstream(ctx context.Context) {
+	status := "unreachable"
+	if a.upstreamReachable(ctx) {
+		status = "reachable"
+	}
+	a.mu.Lock()
+	a.upstream = status
+	a.mu.Unlock()
+}
+
+// runUpstreamProbeLoop probes the upstream every upstreamProbeInterval until
+// ctx is cancelled, so that readiness checks never wait for the upstream.
+func (a *app) runUpstreamProbeLoop(ctx context.Context) {
+	ticker := time.NewTicker(upstreamProbeInterval)
+	defer ticker.Stop()
+	for {
+		a.probeUpstream(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// upstreamReachable reports whether the semester index page answers within
+// upstreamProbeTimeout.
+func (a *app) upstreamReachable(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, upstreamProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, fmt.Sprintf("%s/", a.getEndpoint()), nil)
+	if err != nil {
+		return false
+	}
+	resp, err := tracingTransport{base: http.DefaultTransport}.RoundTrip(req)
 	if err != nil {
-		a.logger.Error("error while visting page", slog.String("error", err.Error()))
+		return false
 	}
+	resp.Body.Close()
+	return resp.StatusCode < http.StatusInternalServerError
 }
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const subjectPage = `<html><body>
+<div class="course">
+  <div class="courseinfo"><div class="courseattrContainer">
+    <div class="subject">COMP 1021 - Introduction to Computer Science (3 units)</div>
+    <div class="courseattr"><div class="popupdetail"><table>
+      <tr><th>CO-LIST WITH</th><td>ISOM 1021, COMP 1021</td></tr>
+      <tr><th>DESCRIPTION</th><td>Basic concepts of computing.</td></tr>
+      <tr><th>PRE-REQUISITE</th><td>Level 3 or above in HKDSE Mathematics</td></tr>
+      <tr><th>EXCLUSION</th><td>COMP 1022P, COMP 1022Q</td></tr>
+    </table></div></div>
+  </div></div>
+  <table class="sections">
+    <tr class="newsect">
+      <td>L1 (1001)</td><td>TuTh 03:00PM - 04:20PM</td><td>Lecture Theater A</td>
+      <td><div class="instructorList"><a>LAM, Gibson</a></div></td>
+      <td><div class="taListContainer"><div class="taList"></div></div></td>
+      <td>200<div class="quotadetail">Reserved 20</div></td><td>180</td><td>20</td><td>0</td>
+    </tr>
+    <tr class="newsect">
+      <td>LA1 (1002)</td><td>Mo 09:00AM - 10:50AM</td><td>Rm 4210</td>
+      <td><div class="instructorList"><a>LAM, Gibson</a></div></td>
+      <td><div class="taListContainer"><div class="taList"><a>TA, Alice</a></div></div></td>
+      <td>40</td><td>40</td><td>0</td><td>3</td>
+    </tr>
+  </table>
+</div>
+</body></html>`
+
+// upstreamServer serves a semester index listing the given departments for
+// every semester, with subjectPage as the page of COMP and no other
+// department pages.
+func upstreamServer(t *testing.T, departments ...string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/subject/COMP"):
+			fmt.Fprint(w, subjectPage)
+		case strings.HasSuffix(r.URL.Path, "/"):
+			fmt.Fprint(w, departmentIndex(departments...))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestGetCourse_ParsesSections(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, subjectPage)
+	}))
+	defer srv.Close()
+
+	a := testApp()
+	a.endpoint = srv.URL
+	a.GetCourse(context.Background(), "COMP")
+
+	course, ok := a.cache["COMP1021"]
+	if !ok {
+		t.Fatal("GetCourse() did not cache COMP1021")
+	}
+	if course.Credits != 3 {
+		t.Errorf("Credits = %v, want 3", course.Credits)
+	}
+	want := []Section{
+		{Code: "L1", Time: "TuTh 03:00PM - 04:20PM", Room: "Lecture Theater A", Quota: 200, Enrol: 180, Avail: 20},
+		{Code: "LA1", Time: "Mo 09:00AM - 10:50AM", Room: "Rm 4210", Quota: 40, Enrol: 40, Wait: 3},
+	}
+	if len(course.Schedule) != len(want) {
+		t.Fatalf("len(Schedule) = %d, want %d", len(course.Schedule), len(want))
+	}
+	for i := range want {
+		if course.Schedule[i] != want[i] {
+			t.Errorf("Schedule[%d] = %+v, want %+v", i, course.Schedule[i], want[i])
+		}
+	}
+	if got := course.Instructors["TA, Alice"]; len(got) != 1 || got[0] != "LA1" {
+		t.Errorf("Instructors[TA, Alice] = %v, want [LA1]", got)
+	}
+	if course.Description != "Basic concepts of computing." {
+		t.Errorf("Description = %q, want %q", course.Description, "Basic concepts of computing.")
+	}
+	if course.Prerequisites != "Level 3 or above in HKDSE Mathematics" {
+		t.Errorf("Prerequisites = %q", course.Prerequisites)
+	}
+	if got := course.Exclusions; len(got) != 2 || got[0] != "COMP1022P" || got[1] != "COMP1022Q" {
+		t.Errorf("Exclusions = %v, want [COMP1022P COMP1022Q]", got)
+	}
+	if got := course.CoListWith; len(got) != 1 || got[0] != "ISOM1021" {
+		t.Errorf("CoListWith = %v, want [ISOM1021]", got)
+	}
+	if alias, ok := a.cachedCourseLocked("ISOM1021"); !ok || alias != course {
+		t.Error("cross-listed code ISOM1021 does not resolve to COMP1021")
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"40", 40},
+		{" 200Reserved 20", 200},
+		{"", 0},
+		{"n/a", 0},
+	}
+	for _, tt := range tests {
+		if got := parseCount(tt.in); got != tt.want {
+			t.Errorf("parseCount(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Weights of the course fields a search term can match. A course scores the
+// weight of the most important field containing the term.
+const (
+	weightCode        = 4
+	weightTitle       = 3
+	weightInstructor  = 2
+	weightDescription = 1
+)
+
+// Scores of a single query term against an indexed token, before weighting.
+const (
+	scoreExact  = 1.0
+	scorePrefix = 0.8
+	// scoreTypo is reduced by scorePerEdit for every edit needed to turn
+	// the query term into the token.
+	scoreTypo    = 0.8
+	scorePerEdit = 0.2
+)
+
+// Every query term is compared with every indexed token while the cache is
+// locked, so queries are kept short.
+const (
+	maxSearchQueryLength = 100
+	maxSearchTerms       = 8
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+)
+
+// stopWords are too common in titles and descriptions to be worth indexing.
+var stopWords = []string{"a", "an", "and", "for", "in", "of", "on", "or", "the", "to", "with"}
+
+// searchIndex is an inverted index over the cached courses of the current
+// term. It is guarded by app.mu.
+type searchIndex struct {
+	postings map[string]map[string]float64 // by token, then course code
+	tokens   map[string][]string           // by course code
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		postings: make(map[string]map[string]float64),
+		tokens:   make(map[string][]string),
+	}
+}
+
+// searchTokens splits s into lower-case words, dropping stop words.
+func searchTokens(s string) []string {
+	var tokens []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !slices.Contains(stopWords, word) {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
+
+// add indexes course, replacing any earlier version of it.
+func (idx *searchIndex) add(course *Course) {
+	idx.remove(course.Code)
+	weights := make(map[string]float64)
+	index := func(weight float64, tokens ...string) {
+		for _, token := range tokens {
+			weights[token] = max(weights[token], weight)
+		}
+	}
+	index(weightCode, searchTokens(course.Code)...)
+	if code, err := parseCourseCode(course.Code); err == nil {
+		// Users search for "comp 1021" as often as for "comp1021".
+		index(weightCode, strings.ToLower(code.Department), strings.ToLower(code.Number+code.Suffix))
+	}
+	for _, alias := range course.CoListWith {
+		index(weightCode, searchTokens(alias)...)
+	}
+	index(weightTitle, searchTokens(course.Title)...)
+	for name := range course.Instructors {
+		index(weightInstructor, searchTokens(name)...)
+	}
+	index(weightDescription, searchTokens(course.Description)...)
+
+	for token, weight := range weights {
+		if idx.postings[token] == nil {
+			idx.postings[token] = make(map[string]float64)
+		}
+		idx.postings[token][course.Code] = weight
+		idx.tokens[course.Code] = append(idx.tokens[course.Code], token)
+	}
+}
+
+func (idx *searchIndex) remove(code string) {
+	for _, token := range idx.tokens[code] {
+		delete(idx.postings[token], code)
+		if len(idx.postings[token]) == 0 {
+			delete(idx.postings, token)
+		}
+	}
+	delete(idx.tokens, code)
+}
+
+// searchHit is a course matching every term of a query.
+type searchHit struct {
+	code  string
+	score float64
+}
+
+// search returns the courses matching every term of query, best first.
+func (idx *searchIndex) search(query string) []searchHit {
+	var scores map[string]float64
+	for _, term := range searchTokens(query) {
+		best := make(map[string]float64)
+		for token, postings := range idx.postings {
+			similarity := termSimilarity(term, token)
+			if similarity == 0 {
+				continue
+			}
+			for code, weight := range postings {
+				best[code] = max(best[code], similarity*weight)
+			}
+		}
+		if scores == nil {
+			scores = best
+			continue
+		}
+		for code, score := range scores {
+			if b, ok := best[code]; ok {
+				scores[code] = score + b
+			} else {
+				delete(scores, code)
+			}
+		}
+	}
+	hits := make([]searchHit, 0, len(scores))
+	for code, score := range scores {
+		hits = append(hits, searchHit{code: code, score: score})
+	}
+	slices.SortFunc(hits, func(x, y searchHit) int {
+		if c := cmp.Compare(y.score, x.score); c != 0 {
+			return c
+		}
+		return strings.Compare(x.code, y.code)
+	})
+	return hits
+}
+
+// maxEdits returns the number of typos tolerated in a query term, which
+// grows with its length so that short terms do not match everything.
+func maxEdits(term string) int {
+	switch n := len([]rune(term)); {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// termSimilarity scores how well a query term matches an indexed token, or
+// returns 0 if it does not match at all.
+func termSimilarity(term, token string) float64 {
+	if term == token {
+		return scoreExact
+	}
+	if len(term) >= 2 && strings.HasPrefix(token, term) {
+		return scorePrefix
+	}
+	limit := maxEdits(term)
+	if limit == 0 || abs(utf8.RuneCountInString(term)-utf8.RuneCountInString(token)) > limit {
+		return 0
+	}
+	if d := editDistance(term, token, limit); d <= limit {
+		return scoreTypo - scorePerEdit*float64(d)
+	}
+	return 0
+}
+
+// editDistance returns the optimal string alignment distance between a and
+// b, counting insertions, deletions, substitutions and transpositions of
+// adjacent letters. Distances above limit are reported as limit+1.
+func editDistance(a, b string, limit int) int {
+	s, t := []rune(a), []rune(b)
+	if abs(len(s)-len(t)) > limit {
+		return limit + 1
+	}
+	// Keep the previous two rows of the distance matrix.
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, cur[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return min(prev[len(t)], limit+1)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// parseLimit reads the limit query parameter, returning def when it is
+// absent.
+func parseLimit(c echo.Context, def, maximum int) (int, error) {
+	param := c.QueryParam("limit")
+	if param == "" {
+		return def, nil
+	}
+	limit, err := strconv.Atoi(param)
+	if err != nil || limit < 1 || limit > maximum {
+		return 0, ErrInvalidLimit
+	}
+	return limit, nil
+}
+
+func (a *app) HandleSearchCourses(c echo.Context) error {
+	a.logger.Info("GET /v1/search")
+	query := strings.TrimSpace(c.QueryParam("q"))
+	terms := searchTokens(query)
+	if len(terms) == 0 {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: ErrEmptySearchQuery.Error(),
+		})
+		return nil
+	}
+	if len(query) > maxSearchQueryLength || len(terms) > maxSearchTerms {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: fmt.Sprintf("%s: at most %d bytes and %d words", ErrSearchQueryTooLong, maxSearchQueryLength, maxSearchTerms),
+		})
+		return nil
+	}
+	limit, err := parseLimit(c, defaultSearchLimit, maxSearchLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	c.JSON(http.StatusOK, a.searchCourses(query, limit))
+	return nil
+}
+
+// searchCourses returns up to limit cached courses of the current term
+// matching query.
+func (a *app) searchCourses(query string, limit int) searchResponse {
+	resp := searchResponse{Query: query, Results: []searchResult{}}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.search == nil {
+		return resp
+	}
+	for _, hit := range a.search.search(query) {
+		if len(resp.Results) == limit {
+			break
+		}
+		course, ok := a.cache[hit.code]
+		if !ok {
+			continue
+		}
+		resp.Results = append(resp.Results, searchResult{
+			Code:    course.Code,
+			Title:   course.Title,
+			Credits: course.Credits,
+			Score:   hit.score,
+		})
+	}
+	return resp
+}
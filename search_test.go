@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func searchApp() *app {
+	return fixtureApp(
+		&Course{Code: "COMP4211", Title: "Machine Learning", Credits: 3, Instructors: map[string][]string{"ZHANG, Nevin": {"L1"}}},
+		&Course{Code: "COMP2211", Title: "Exploring Artificial Intelligence", Credits: 3, Description: "Introduces machine learning and its applications."},
+		&Course{Code: "MATH2111", Title: "Matrix Algebra and Applications", Credits: 4},
+		&Course{Code: "MATH2121", Title: "Linear Algebra", Credits: 4},
+	)
+}
+
+func searchCodes(a *app, query string) []string {
+	var codes []string
+	for _, r := range a.searchCourses(query, maxSearchLimit).Results {
+		codes = append(codes, r.Code)
+	}
+	return codes
+}
+
+func TestSearchCourses(t *testing.T) {
+	a := searchApp()
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		// A title match ranks above a description match.
+		{"machine learning", []string{"COMP4211", "COMP2211"}},
+		{"machine learnin", []string{"COMP4211", "COMP2211"}},
+		{"linear algbra", []string{"MATH2121"}},
+		{"algebra", []string{"MATH2111", "MATH2121"}},
+		{"comp 4211", []string{"COMP4211"}},
+		{"nevin", []string{"COMP4211"}},
+		{"quantum", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := searchCodes(a, tt.query)
+			if len(got) < len(tt.want) {
+				t.Fatalf("search(%q) = %v, want prefix %v", tt.query, got, tt.want)
+			}
+			for i, code := range tt.want {
+				if got[i] != code {
+					t.Fatalf("search(%q) = %v, want prefix %v", tt.query, got, tt.want)
+				}
+			}
+			if tt.want == nil && len(got) != 0 {
+				t.Errorf("search(%q) = %v, want no results", tt.query, got)
+			}
+		})
+	}
+}
+
+func TestSearchIndex_ReplacesCourse(t *testing.T) {
+	a := searchApp()
+	a.remember(&CourseParsingResult{Code: "COMP4211", Course: &Course{Code: "COMP4211", Title: "Deep Learning"}})
+
+	if got := searchCodes(a, "machine"); len(got) != 1 || got[0] != "COMP2211" {
+		t.Errorf("search(machine) = %v, want [COMP2211]", got)
+	}
+	if got := searchCodes(a, "deep"); len(got) != 1 || got[0] != "COMP4211" {
+		t.Errorf("search(deep) = %v, want [COMP4211]", got)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"algebra", "algebra", 0},
+		{"algbra", "algebra", 1},
+		{"lienar", "linear", 1},
+		{"machin", "machine", 1},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, 3); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+	if got := editDistance("kitten", "sitting", 1); got != 2 {
+		t.Errorf("editDistance(kitten, sitting, 1) = %d, want 2", got)
+	}
+}
+
+func TestHandleSearchCourses(t *testing.T) {
+	a := searchApp()
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/search?q=linear+algbra&limit=1", a)
+
+	if err := a.HandleSearchCourses(c); err != nil {
+		t.Fatalf("HandleSearchCourses() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var resp searchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Results) != 1 || resp.Results[0].Code != "MATH2121" || resp.Results[0].Credits != 4 {
+		t.Errorf("results = %+v, want MATH2121 only", resp.Results)
+	}
+}
+
+func TestHandleSearchCourses_BadRequest(t *testing.T) {
+	for _, target := range []string{
+		"/v1/search",
+		"/v1/search?q=+the+",
+		"/v1/search?q=comp&limit=0",
+		"/v1/search?q=comp&limit=x",
+		"/v1/search?q=" + strings.Repeat("a", maxSearchQueryLength+1),
+		"/v1/search?q=" + strings.Repeat("data+", maxSearchTerms) + "mining",
+	} {
+		a := searchApp()
+		c, rec := setupHandlerTest(http.MethodGet, target, a)
+		if err := a.HandleSearchCourses(c); err != nil {
+			t.Fatalf("HandleSearchCourses() error: %v", err)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("GET %s status = %d, want %d", target, rec.Code, http.StatusBadRequest)
+		}
+	}
+}
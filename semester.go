@@ -4,72 +4,175 @@ import (
 	"fmt"
 	"strconv"
 	"time"
+	// Embedded so that campusTime loads in images without zoneinfo.
+	_ "time/tzdata"
 )
 
+// campusTime is the time zone terms begin and end in, whatever the server's
+// local time zone.
+var campusTime = mustLoadLocation("Asia/Hong_Kong")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
 type semester struct {
-	Code   string `json:"code"`
-	Name   string `json:"name"`
-	Year   string `json:"year"`
-	Cohort string `json:"cohort"`
+	Code   string        `json:"code"`
+	Name   string        `json:"name"`
+	Year   string        `json:"year"`
+	Cohort string        `json:"cohort"`
+	Start  string        `json:"start"`
+	End    string        `json:"end"`
+	Links  semesterLinks `json:"links"`
+}
+
+// semesterLinks point to a semester and its neighbours in the calendar.
+type semesterLinks struct {
+	Self     string `json:"self"`
+	Previous string `json:"previous"`
+	Next     string `json:"next"`
+}
+
+func semesterURL(code string) string {
+	return "/v1/semesters/" + code
 }
 
 const centuryPrefix = "20"
 
-func parseSemester(code string) (semester, error) {
-	semesterNames := map[string]string{
-		"10": "Fall",
-		"20": "Winter",
-		"30": "Spring",
-		"40": "Summer",
+// dateLayout formats the first and last day of a semester.
+const dateLayout = time.DateOnly
+
+// term identifies a semester by the year in which its academic year begins
+// and the index of its season in the calendar.
+type term struct {
+	year   int
+	season int
+}
+
+func (cal *academicCalendar) termCode(t term) string {
+	return fmt.Sprintf("%02d%s", t.year%100, cal.Seasons[t.season].Code)
+}
+
+// next returns the term following t.
+func (cal *academicCalendar) next(t term) term {
+	if t.season+1 < len(cal.Seasons) {
+		return term{year: t.year, season: t.season + 1}
+	}
+	return term{year: t.year + 1}
+}
+
+// previous returns the term preceding t.
+func (cal *academicCalendar) previous(t term) term {
+	if t.season > 0 {
+		return term{year: t.year, season: t.season - 1}
+	}
+	return term{year: t.year - 1, season: len(cal.Seasons) - 1}
+}
+
+// compareTerms orders terms chronologically.
+func compareTerms(x, y term) int {
+	if x.year != y.year {
+		return x.year - y.year
 	}
+	return x.season - y.season
+}
+
+// parseTerm splits a semester code into the academic year and season it
+// names.
+func (cal *academicCalendar) parseTerm(code string) (term, error) {
 	if len(code) < 3 {
-		return semester{}, ErrInvalidSemesterCode
+		return term{}, ErrInvalidSemesterCode
 	}
 	seasonIndicator := code[len(code)-2:]
-	if _, ok := semesterNames[seasonIndicator]; !ok {
-		return semester{}, ErrInvalidSemesterCode
+	season := cal.seasonIndex(seasonIndicator)
+	if season < 0 {
+		return term{}, ErrInvalidSemesterCode
 	}
-	inputSemesterPrefix := code[:len(code)-2]
-	inputYear, err := strconv.Atoi(inputSemesterPrefix)
+	inputYear, err := strconv.Atoi(code[:len(code)-2])
 	if err != nil {
-		return semester{}, fmt.Errorf("semester code integer conversion for year: %w", err)
+		return term{}, fmt.Errorf("semester code integer conversion for year: %w", err)
 	}
-	inputSeason, err := strconv.Atoi(seasonIndicator)
+	return term{year: 2000 + inputYear, season: season}, nil
+}
+
+func (cal *academicCalendar) parseSemester(code string) (semester, error) {
+	t, err := cal.parseTerm(code)
 	if err != nil {
-		return semester{}, fmt.Errorf("semester code integer conversion for season: %w", err)
+		return semester{}, err
 	}
+	inputSemesterPrefix := code[:len(code)-2]
+	inputYear := t.year - 2000
+	s := cal.Seasons[t.season]
 	cohort := fmt.Sprintf("%s%s - %s%d", centuryPrefix, inputSemesterPrefix, centuryPrefix, inputYear+1)
-	var year string
-	if inputSeason > 20 {
-		year = fmt.Sprintf("%s%d", centuryPrefix, inputYear+1)
-	} else {
-		year = fmt.Sprintf("%s%s", centuryPrefix, inputSemesterPrefix)
-	}
+	start, end := cal.bounds(t, time.UTC)
 	return semester{
 		Code:   code,
-		Name:   fmt.Sprintf("%s %s", cohort, semesterNames[seasonIndicator]),
-		Year:   year,
+		Name:   fmt.Sprintf("%s %s", cohort, s.Name),
+		Year:   fmt.Sprintf("%s%d", centuryPrefix, inputYear+s.YearOffset),
 		Cohort: cohort,
+		Start:  start.Format(dateLayout),
+		// end is exclusive; report the last day of the term.
+		End: end.AddDate(0, 0, -1).Format(dateLayout),
+		Links: semesterLinks{
+			Self:     semesterURL(code),
+			Previous: semesterURL(cal.termCode(cal.previous(t))),
+			Next:     semesterURL(cal.termCode(cal.next(t))),
+		},
 	}, nil
 }
 
-func getSemesterCodeForTime(t time.Time) (string, error) {
-	year := t.Year()
-	month := t.Month()
-	if month < time.September {
-		prefix := fmt.Sprintf("%02d", (year-1)%100)
-		if month >= time.February {
-			if month >= time.June {
-				return prefix + "40", nil
-			}
-			return prefix + "30", nil
+// start returns the first instant of t in loc.
+func (cal *academicCalendar) start(t term, loc *time.Location) time.Time {
+	if dates, ok := cal.explicitTerm(cal.termCode(t)); ok {
+		return onDate(dates.start, loc)
+	}
+	s := cal.Seasons[t.season]
+	year := t.year
+	if s.start.before(cal.Seasons[0].start) {
+		year++
+	}
+	return time.Date(year, s.start.month, s.start.day, 0, 0, 0, 0, loc)
+}
+
+// bounds returns the start of t and the instant it ends, which is the start
+// of the following term unless the calendar gives t an explicit end date.
+func (cal *academicCalendar) bounds(t term, loc *time.Location) (start, end time.Time) {
+	start = cal.start(t, loc)
+	if dates, ok := cal.explicitTerm(cal.termCode(t)); ok {
+		return start, onDate(dates.end, loc).AddDate(0, 0, 1)
+	}
+	return start, cal.start(cal.next(t), loc)
+}
+
+// termForTime returns the latest term that has started at t.
+func (cal *academicCalendar) termForTime(t time.Time) term {
+	// Terms start at most a year after their academic year begins, so the
+	// term in progress belongs to one of the last three academic years.
+	current := term{year: t.Year() - 2}
+	for candidate := current; candidate.year <= t.Year(); candidate = cal.next(candidate) {
+		if cal.start(candidate, t.Location()).After(t) {
+			break
 		}
-		return prefix + "20", nil
+		current = candidate
 	}
-	prefix := fmt.Sprintf("%02d", year%100)
-	return prefix + "10", nil
+	return current
+}
+
+func (cal *academicCalendar) semesterCodeForTime(t time.Time) (string, error) {
+	return cal.termCode(cal.termForTime(t)), nil
+}
+
+func (cal *academicCalendar) currentSemesterCode() (string, error) {
+	return cal.semesterCodeForTime(time.Now().In(campusTime))
 }
 
-func getCurrentSemesterCode() (string, error) {
-	return getSemesterCodeForTime(time.Now())
+// semesterBoundsForTime returns the start of the semester containing t and
+// the start of the semester after it, in t's location.
+func (cal *academicCalendar) semesterBoundsForTime(t time.Time) (start, next time.Time) {
+	current := cal.termForTime(t)
+	return cal.start(current, t.Location()), cal.start(cal.next(current), t.Location())
 }
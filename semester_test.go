@@ -14,19 +14,66 @@ import (
 func testApp() *app {
 	return &app{
 		cache:           make(map[string]*Course),
-		departmentCache: []string{},
+		departmentCache: []department{},
+		calendar:        defaultCalendar(),
 		logger:          slog.New(slog.NewTextHandler(io.Discard, nil)),
 	}
 }
 
+// fixtureCourses returns the courses shared by the handler tests, fresh for
+// each call: COMP1021 and COMP1022P, which exclude each other, and MATH1013.
+func fixtureCourses() []*Course {
+	return []*Course{
+		{
+			Code:       "COMP1021",
+			Title:      "Introduction to Computer Science",
+			Credits:    3,
+			CoListWith: []string{"ISOM1021"},
+			Exclusions: []string{"COMP1022P", "COMP1022Q"},
+			Sections:   []string{"L1", "LA1"},
+			Instructors: map[string][]string{
+				"LAM, Gibson": {"L1"},
+				"TA, Alice":   {"LA1"},
+				"TA, Bob":     {"LA1"},
+			},
+			Schedule: []Section{
+				{Code: "L1", Time: "TuTh 03:00PM - 04:20PM", Room: "LTA", Quota: 200, Enrol: 180, Avail: 20},
+				{Code: "LA1", Time: "Mo 09:00AM - 10:50AM", Room: "Rm 4210", Quota: 40, Enrol: 40, Wait: 3},
+			},
+		},
+		{
+			Code:        "COMP1022P",
+			Title:       "Introduction to Python Programming",
+			Credits:     3,
+			Exclusions:  []string{"COMP1021", "COMP1022Q"},
+			Sections:    []string{"L1"},
+			Instructors: map[string][]string{"LAM, Gibson": {"L1"}},
+			Schedule: []Section{
+				{Code: "L1", Time: "TuTh 03:00PM - 04:20PM", Room: "LTB", Quota: 150, Enrol: 150},
+			},
+		},
+		{Code: "MATH1013", Title: "Calculus IB", Credits: 3, Exclusions: []string{"MATH1023"}},
+	}
+}
+
+// fixtureApp returns a testApp holding the fixture courses and any others
+// given, cached and indexed as if scraped.
+func fixtureApp(courses ...*Course) *app {
+	a := testApp()
+	for _, course := range append(fixtureCourses(), courses...) {
+		a.remember(&CourseParsingResult{Code: course.Code, Course: course})
+	}
+	return a
+}
+
 func TestGetCurrentSemesterCode(t *testing.T) {
-	code, err := getCurrentSemesterCode()
+	code, err := defaultCalendar().currentSemesterCode()
 	if err != nil {
-		t.Fatalf("getCurrentSemesterCode() returned error: %v", err)
+		t.Fatalf("defaultCalendar().currentSemesterCode() returned error: %v", err)
 	}
 	pattern := regexp.MustCompile(`^\d{2}(10|20|30|40)$`)
 	if !pattern.MatchString(code) {
-		t.Errorf("getCurrentSemesterCode() = %q, want match for %s", code, pattern)
+		t.Errorf("defaultCalendar().currentSemesterCode() = %q, want match for %s", code, pattern)
 	}
 }
 
@@ -84,7 +131,7 @@ func TestParseSemester(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s, err := parseSemester(tt.code)
+			s, err := defaultCalendar().parseSemester(tt.code)
 			if tt.wantErr {
 				if err == nil {
 					t.Fatalf("parseSemester(%q) expected error, got nil", tt.code)
@@ -108,7 +155,7 @@ func TestParseSemester(t *testing.T) {
 }
 
 func TestParseSemester_SentinelError(t *testing.T) {
-	_, err := parseSemester("2550")
+	_, err := defaultCalendar().parseSemester("2550")
 	if err == nil {
 		t.Fatal("expected error for invalid code 2550")
 	}
@@ -118,7 +165,7 @@ func TestParseSemester_SentinelError(t *testing.T) {
 }
 
 func TestParseSemester_Fields(t *testing.T) {
-	s, err := parseSemester("2510")
+	s, err := defaultCalendar().parseSemester("2510")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -142,7 +189,7 @@ func TestParseSemester_ConcurrentSafe(t *testing.T) {
 	codes := []string{"2510", "2520", "2530", "2540"}
 	for _, code := range codes {
 		wg.Go(func() {
-			_, _ = parseSemester(code)
+			_, _ = defaultCalendar().parseSemester(code)
 		})
 	}
 	wg.Wait()
@@ -199,7 +246,7 @@ func TestGetSemesterCodeForTime(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getSemesterCodeForTime(tt.time)
+			got, err := defaultCalendar().semesterCodeForTime(tt.time)
 			if err != nil {
 				t.Fatalf("getSemesterCodeForTime() error: %v", err)
 			}
@@ -209,3 +256,22 @@ func TestGetSemesterCodeForTime(t *testing.T) {
 		})
 	}
 }
+
+func TestSemesterBoundsForTime(t *testing.T) {
+	tests := []struct {
+		time  time.Time
+		start time.Time
+		next  time.Time
+	}{
+		{time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 1, 20, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 5, 31, 23, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 8, 31, 0, 0, 0, 0, time.UTC), time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		start, next := defaultCalendar().semesterBoundsForTime(tt.time)
+		if !start.Equal(tt.start) || !next.Equal(tt.next) {
+			t.Errorf("semesterBoundsForTime(%v) = %v, %v; want %v, %v", tt.time, start, next, tt.start, tt.next)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/labstack/echo/v4"
+)
+
+// resolveSemesterCode turns the "current" and "next" aliases into semester
+// codes; other codes are returned unchanged.
+func (a *app) resolveSemesterCode(code string) (string, error) {
+	switch code {
+	case "current", "next":
+		current, err := a.calendar.currentSemesterCode()
+		if err != nil || code == "current" {
+			return current, err
+		}
+		t, err := a.calendar.parseTerm(current)
+		if err != nil {
+			return "", err
+		}
+		return a.calendar.termCode(a.calendar.next(t)), nil
+	}
+	return code, nil
+}
+
+// semesterRange lists every term from the first to the last code of a range
+// such as 2410-2540, inclusive.
+func (cal *academicCalendar) semesterRange(r string) ([]string, error) {
+	first, last, ok := strings.Cut(r, "-")
+	if !ok {
+		return nil, fmt.Errorf("semester range %q must be FIRST-LAST", r)
+	}
+	from, err := cal.parseTerm(first)
+	if err != nil {
+		return nil, fmt.Errorf("semester range start %q: %w", first, err)
+	}
+	to, err := cal.parseTerm(last)
+	if err != nil {
+		return nil, fmt.Errorf("semester range end %q: %w", last, err)
+	}
+	if compareTerms(from, to) > 0 {
+		return nil, fmt.Errorf("semester range %q ends before it starts", r)
+	}
+	var codes []string
+	for t := from; compareTerms(t, to) <= 0; t = cal.next(t) {
+		codes = append(codes, cal.termCode(t))
+	}
+	return codes, nil
+}
+
+// semesterPath matches the path of a semester's index page below the base
+// URL.
+var semesterPath = regexp.MustCompile(`^/?([0-9]+)/?$`)
+
+// availableSemesters returns the codes of the semesters to list, from the
+// configured range or, without one, from the links to other terms on the
+// upstream semester index, in chronological order. A dataset holds only its
+// own semester. Discovered semesters are cached until the current semester
+// changes or the refresh loop discovers them again.
+func (a *app) availableSemesters(ctx context.Context) ([]string, error) {
+	switch {
+	case a.config.SemesterRange != "":
+		return a.calendar.semesterRange(a.config.SemesterRange)
+	case a.offline():
+		return []string{a.semester()}, nil
+	}
+	a.mu.RLock()
+	codes := a.semesters
+	a.mu.RUnlock()
+	if codes != nil {
+		return codes, nil
+	}
+	return a.refreshSemesters(ctx)
+}
+
+// refreshSemesters discovers the semesters linked from the upstream index of
+// the current semester and caches them for availableSemesters.
+func (a *app) refreshSemesters(ctx context.Context) ([]string, error) {
+	endpoint := a.getEndpoint()
+	codes, err := a.discoverSemesters(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	// A rollover during discovery makes the result stale.
+	if a.endpoint == endpoint {
+		a.semesters = codes
+	}
+	a.mu.Unlock()
+	return codes, nil
+}
+
+// discoverSemesters returns the codes of the semesters linked from the
+// semester index at endpoint, and of the semester itself, in chronological
+// order.
+func (a *app) discoverSemesters(ctx context.Context, endpoint string) ([]string, error) {
+	var terms []term
+	collector := colly.NewCollector(colly.StdlibContext(ctx))
+	collector.WithTransport(tracingTransport{base: http.DefaultTransport})
+	collector.OnHTML("a[href]", func(e *colly.HTMLElement) {
+		rest, ok := strings.CutPrefix(e.Request.AbsoluteURL(e.Attr("href")), a.config.BaseURL)
+		if !ok {
+			return
+		}
+		m := semesterPath.FindStringSubmatch(rest)
+		if m == nil {
+			return
+		}
+		t, err := a.calendar.parseTerm(m[1])
+		if err != nil || slices.Contains(terms, t) {
+			return
+		}
+		terms = append(terms, t)
+	})
+	if err := collector.Visit(fmt.Sprintf("%s/", endpoint)); err != nil {
+		return nil, fmt.Errorf("semester discovery: %w", err)
+	}
+	// The index may not link to the term it belongs to.
+	if current, err := a.calendar.parseTerm(path.Base(endpoint)); err == nil && !slices.Contains(terms, current) {
+		terms = append(terms, current)
+	}
+	slices.SortFunc(terms, compareTerms)
+	codes := make([]string, 0, len(terms))
+	for _, t := range terms {
+		codes = append(codes, a.calendar.termCode(t))
+	}
+	return codes, nil
+}
+
+func (a *app) HandleListSemesters(c echo.Context) error {
+	a.logger.Info("GET /v1/semesters")
+	codes, err := a.availableSemesters(c.Request().Context())
+	if err != nil {
+		c.JSON(http.StatusBadGateway, errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	semesters := make([]semester, 0, len(codes))
+	for _, code := range codes {
+		s, err := a.calendar.parseSemester(code)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse{
+				Status:  "error",
+				Message: err.Error(),
+			})
+			return nil
+		}
+		semesters = append(semesters, s)
+	}
+	c.JSON(http.StatusOK, semesters)
+	return nil
+}
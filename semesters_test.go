@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestParseSemester_Links(t *testing.T) {
+	tests := []struct {
+		code           string
+		previous, next string
+	}{
+		{"2510", "/v1/semesters/2440", "/v1/semesters/2520"},
+		{"2530", "/v1/semesters/2520", "/v1/semesters/2540"},
+		{"2540", "/v1/semesters/2530", "/v1/semesters/2610"},
+	}
+	for _, tt := range tests {
+		s, err := defaultCalendar().parseSemester(tt.code)
+		if err != nil {
+			t.Fatalf("parseSemester(%q) error: %v", tt.code, err)
+		}
+		want := semesterLinks{Self: "/v1/semesters/" + tt.code, Previous: tt.previous, Next: tt.next}
+		if s.Links != want {
+			t.Errorf("parseSemester(%q).Links = %+v, want %+v", tt.code, s.Links, want)
+		}
+	}
+}
+
+func TestSemesterRange(t *testing.T) {
+	got, err := defaultCalendar().semesterRange("2430-2520")
+	if err != nil {
+		t.Fatalf("semesterRange() error: %v", err)
+	}
+	if want := []string{"2430", "2440", "2510", "2520"}; !slices.Equal(got, want) {
+		t.Errorf("semesterRange() = %v, want %v", got, want)
+	}
+	for _, r := range []string{"2430", "2430-2550", "2520-2430", "x-2430"} {
+		if _, err := defaultCalendar().semesterRange(r); err == nil {
+			t.Errorf("semesterRange(%q) error = nil, want error", r)
+		}
+	}
+	if _, err := loadConfig([]string{"-semester-range", "2520-2430"}); err == nil {
+		t.Error("loadConfig() with reversed semester range error = nil, want error")
+	}
+}
+
+func TestHandleListSemesters_Upstream(t *testing.T) {
+	var base string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><body>
+			<a href="/wcq/cgi-bin/2510/">2025-26 Fall</a>
+			<a href="%s/2440">2024-25 Summer</a>
+			<a href="../2530/">2025-26 Spring</a>
+			<a href="../2550/">Not a term</a>
+			<a href="subject/COMP">COMP</a>
+		</body></html>`, base)
+	}))
+	defer srv.Close()
+	base = srv.URL + "/wcq/cgi-bin"
+
+	a := testApp()
+	a.config.BaseURL = base
+	a.endpoint = base + "/2520"
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/semesters", a)
+	if err := a.HandleListSemesters(c); err != nil {
+		t.Fatalf("HandleListSemesters() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var got []semester
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	var codes []string
+	for _, s := range got {
+		codes = append(codes, s.Code)
+	}
+	if want := []string{"2440", "2510", "2520", "2530"}; !slices.Equal(codes, want) {
+		t.Errorf("semesters = %v, want %v", codes, want)
+	}
+}
+
+func TestHandleListSemesters_ConfiguredRange(t *testing.T) {
+	a := testApp()
+	a.config.SemesterRange = "2510-2530"
+	a.endpoint = "http://127.0.0.1:1/2510"
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/semesters", a)
+	if err := a.HandleListSemesters(c); err != nil {
+		t.Fatalf("HandleListSemesters() error: %v", err)
+	}
+	var got []semester
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if len(got) != 3 || got[0].Code != "2510" || got[2].Code != "2530" {
+		t.Errorf("semesters = %+v, want 2510 to 2530", got)
+	}
+}
+
+func TestHandleListSemesters_UpstreamDown(t *testing.T) {
+	a := testApp()
+	a.endpoint = "http://127.0.0.1:1/2510"
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/semesters", a)
+	if err := a.HandleListSemesters(c); err != nil {
+		t.Fatalf("HandleListSemesters() error: %v", err)
+	}
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+}
+
+func TestHandleGetSemester_Next(t *testing.T) {
+	a := testApp()
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/semesters/next", a)
+	c.SetParamNames("semester")
+	c.SetParamValues("next")
+	if err := a.HandleGetSemester(c); err != nil {
+		t.Fatalf("HandleGetSemester(next) error: %v", err)
+	}
+	var got semester
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	current, _ := defaultCalendar().currentSemesterCode()
+	s, _ := defaultCalendar().parseSemester(current)
+	if got.Links.Self != s.Links.Next {
+		t.Errorf("next semester = %s, want %s", got.Links.Self, s.Links.Next)
+	}
+}
+
+func TestAvailableSemesters_Cached(t *testing.T) {
+	var visits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		visits++
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><a href="../2510/">2025-26 Fall</a></body></html>`)
+	}))
+	defer srv.Close()
+
+	a := testApp()
+	a.config.BaseURL = srv.URL
+	a.endpoint = srv.URL + "/2520"
+	for range 2 {
+		if codes, err := a.availableSemesters(context.Background()); err != nil || !slices.Equal(codes, []string{"2510", "2520"}) {
+			t.Fatalf("availableSemesters() = %v, %v, want [2510 2520]", codes, err)
+		}
+	}
+	if visits != 1 {
+		t.Errorf("semester index fetched %d times, want once", visits)
+	}
+
+	rollOver(a, "2530")
+	codes, err := a.availableSemesters(context.Background())
+	if err != nil || !slices.Equal(codes, []string{"2510", "2530"}) {
+		t.Errorf("availableSemesters() after rollover = %v, %v, want [2510 2530]", codes, err)
+	}
+	if visits != 2 {
+		t.Errorf("semester index fetched %d times, want again after the rollover", visits)
+	}
+}
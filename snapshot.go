@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// snapshot is the catalogue of one semester as persisted after a crawl.
+type snapshot struct {
+	Semester    string       `json:"semester"`
+	TakenAt     time.Time    `json:"taken_at"`
+	Departments []department `json:"departments"`
+	Courses     []*Course    `json:"courses"`
+}
+
+// Sources a course offering can be read from, from least to most recent.
+const (
+	offeringSourceSnapshot = "snapshot"
+	offeringSourceDatabase = "database"
+	offeringSourcePrevious = "previous" // the term served before the last rollover
+	offeringSourceCurrent  = "current"  // the term being served, which may not be fully crawled
+)
+
+// recordedCourse is a course as recorded by one source at a point in time.
+type recordedCourse struct {
+	course     *Course
+	source     string
+	recordedAt time.Time
+}
+
+// offering summarizes a course as offered in one semester.
+type offering struct {
+	Semester     string    `json:"semester"`
+	Season       string    `json:"season"`
+	Title        string    `json:"title"`
+	Credits      float64   `json:"credits"`
+	Instructors  []string  `json:"instructors"`
+	Sections     int       `json:"sections"`
+	Source       string    `json:"source"`
+	LastModified time.Time `json:"last_modified"`
+}
+
+func newOffering(semester, season string, r recordedCourse) offering {
+	instructors := slices.Sorted(maps.Keys(r.course.Instructors))
+	if instructors == nil {
+		instructors = []string{}
+	}
+	return offering{
+		Semester:     semester,
+		Season:       season,
+		Title:        r.course.Title,
+		Credits:      r.course.Credits,
+		Instructors:  instructors,
+		Sections:     len(r.course.Sections),
+		Source:       r.source,
+		LastModified: r.recordedAt,
+	}
+}
+
+// snapshotStore persists one snapshot file per semester in a directory and
+// keeps an index of the courses offered in each.
+type snapshotStore struct {
+	dir string
+
+	mu          sync.RWMutex
+	courses     map[string]map[string]*Course // by semester, then course code
+	departments map[string][]department       // by semester; nil in snapshots predating them
+	takenAt     map[string]time.Time          // by semester
+}
+
+// openSnapshotStore creates dir if needed and indexes the snapshots in it.
+func openSnapshotStore(dir string) (*snapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating snapshot directory: %w", err)
+	}
+	s := &snapshotStore{
+		dir:         dir,
+		courses:     make(map[string]map[string]*Course),
+		departments: make(map[string][]department),
+		takenAt:     make(map[string]time.Time),
+	}
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("reading snapshot: %w", err)
+		}
+		var snap snapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return nil, fmt.Errorf("parsing snapshot %s: %w", name, err)
+		}
+		s.index(snap)
+	}
+	return s, nil
+}
+
+func (s *snapshotStore) index(snap snapshot) {
+	courses := make(map[string]*Course, len(snap.Courses))
+	for _, course := range snap.Courses {
+		courses[course.Code] = course
+	}
+	s.mu.Lock()
+	s.courses[snap.Semester] = courses
+	s.departments[snap.Semester] = snap.Departments
+	s.takenAt[snap.Semester] = snap.TakenAt
+	s.mu.Unlock()
+}
+
+// load returns the snapshot of a semester as a crawl without an endpoint.
+func (s *snapshotStore) load(semester string) (*crawl, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	courses, ok := s.courses[semester]
+	if !ok {
+		return nil, false
+	}
+	return &crawl{
+		departments: slices.Clone(s.departments[semester]),
+		courses:     maps.Clone(courses),
+		crawledAt:   s.takenAt[semester],
+	}, true
+}
+
+// save writes the snapshot of a semester, replacing any earlier one.
+func (s *snapshotStore) save(snap snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first so that a crash never leaves a
+	// truncated snapshot behind.
+	f, err := os.CreateTemp(s.dir, snap.Semester+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), filepath.Join(s.dir, snap.Semester+".json")); err != nil {
+		return err
+	}
+	s.index(snap)
+	return nil
+}
+
+// find returns the course with the given code in every snapshot holding it,
+// by semester.
+func (s *snapshotStore) find(code string) map[string]recordedCourse {
+	found := make(map[string]recordedCourse)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for semester, courses := range s.courses {
+		if course, ok := courses[code]; ok {
+			found[semester] = recordedCourse{course, offeringSourceSnapshot, s.takenAt[semester]}
+		}
+	}
+	return found
+}
+
+// saveSnapshot persists a completed crawl of semester, if a snapshot
+// directory is configured.
+func (a *app) saveSnapshot(semester string, c *crawl) error {
+	if a.snapshots == nil {
+		return nil
+	}
+	courses := make([]*Course, 0, len(c.courses))
+	for _, code := range slices.Sorted(maps.Keys(c.courses)) {
+		courses = append(courses, c.courses[code])
+	}
+	return a.snapshots.save(snapshot{
+		Semester:    semester,
+		TakenAt:     c.crawledAt,
+		Departments: c.departments,
+		Courses:     courses,
+	})
+}
+
+// loadSnapshot serves the snapshot of the current semester, if one was saved,
+// until a crawl replaces it, and reports whether there was one. Snapshots
+// saved before departments were recorded in them are served without
+// reporting ready, since the department list stays empty until a crawl.
+func (a *app) loadSnapshot() bool {
+	if a.snapshots == nil {
+		return false
+	}
+	c, ok := a.snapshots.load(a.semester())
+	if !ok {
+		return false
+	}
+	c.endpoint = a.getEndpoint()
+	complete := c.departments != nil
+	if !complete {
+		c.departments = []department{}
+	}
+	a.replaceCourses(c)
+	if complete {
+		a.markReady(c.crawledAt)
+	}
+	return true
+}
+
+// courseOfferings returns the semesters in which the course with the given
+// code was offered, in chronological order. Snapshots and the database are
+// complemented by the terms held in memory, which may be more recent; each
+// offering names the source it was read from.
+func (a *app) courseOfferings(ctx context.Context, code string) ([]offering, error) {
+	bySemester := make(map[string]recordedCourse)
+	if a.snapshots != nil {
+		bySemester = a.snapshots.find(code)
+	}
+	if a.store != nil {
+		stored, err := a.store.courseHistory(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(bySemester, stored)
+	}
+	a.mu.RLock()
+	if a.previous != nil {
+		if course, ok := a.previous.courses[code]; ok {
+			bySemester[a.previous.semester] = recordedCourse{course, offeringSourcePrevious, a.previous.lastModified}
+		}
+	}
+	if course, ok := a.cache[code]; ok {
+		bySemester[path.Base(a.endpoint)] = recordedCourse{course, offeringSourceCurrent, a.modified[code]}
+	}
+	a.mu.RUnlock()
+
+	type termOffering struct {
+		term term
+		offering
+	}
+	var offerings []termOffering
+	for semester, r := range bySemester {
+		t, err := a.calendar.parseTerm(semester)
+		if err != nil {
+			// Snapshots of terms the configured calendar does not know
+			// cannot be placed in order.
+			continue
+		}
+		offerings = append(offerings, termOffering{t, newOffering(semester, a.calendar.Seasons[t.season].Name, r)})
+	}
+	slices.SortFunc(offerings, func(x, y termOffering) int {
+		return compareTerms(x.term, y.term)
+	})
+	result := make([]offering, 0, len(offerings))
+	for _, o := range offerings {
+		result = append(result, o.offering)
+	}
+	return result, nil
+}
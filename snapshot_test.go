@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSnapshotStore_SaveAndReopen(t *testing.T) {
+	dir := t.TempDir()
+	store, err := openSnapshotStore(dir)
+	if err != nil {
+		t.Fatalf("openSnapshotStore() error: %v", err)
+	}
+	course := &Course{Code: "COMP4211", Title: "Machine Learning", Credits: 3}
+	if err := store.save(snapshot{Semester: "2430", TakenAt: time.Now(), Courses: []*Course{course}}); err != nil {
+		t.Fatalf("save() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2430.json")); err != nil {
+		t.Errorf("snapshot file not written: %v", err)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(dir, "*.tmp")); len(tmp) > 0 {
+		t.Errorf("temporary files left behind: %v", tmp)
+	}
+
+	reopened, err := openSnapshotStore(dir)
+	if err != nil {
+		t.Fatalf("openSnapshotStore() reopen error: %v", err)
+	}
+	found := reopened.find("COMP4211")
+	if got := found["2430"]; got.course == nil || got.course.Title != "Machine Learning" {
+		t.Errorf("find(COMP4211) = %v, want the 2430 offering", found)
+	}
+}
+
+func TestHandleGetCourseOfferings(t *testing.T) {
+	takenAt := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)
+	store, err := openSnapshotStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("openSnapshotStore() error: %v", err)
+	}
+	for _, s := range []snapshot{
+		{Semester: "2530", Courses: []*Course{{Code: "COMP4211", Title: "ML", Sections: []string{"L1"}, Instructors: map[string][]string{"CHAN, Tai Man": {"L1"}}}}},
+		{Semester: "2430", TakenAt: takenAt, Courses: []*Course{{Code: "COMP4211", Title: "ML", Sections: []string{"L1", "L2"}}}},
+		{Semester: "2510", Courses: []*Course{{Code: "COMP1021"}}},
+		// The in-memory term supersedes its older snapshot.
+		{Semester: "2610", Courses: []*Course{{Code: "COMP4211", Title: "Stale"}}},
+	} {
+		if err := store.save(s); err != nil {
+			t.Fatalf("save() error: %v", err)
+		}
+	}
+	a := testApp()
+	a.snapshots = store
+	a.endpoint = "http://127.0.0.1:1/2610"
+	a.remember(&CourseParsingResult{Code: "COMP4211", Course: &Course{Code: "COMP4211", Title: "Machine Learning"}})
+	modified := a.modified["COMP4211"]
+
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/courses/comp4211/offerings", a)
+	c.SetParamNames("course")
+	c.SetParamValues("comp4211")
+	if err := a.HandleGetCourseOfferings(c); err != nil {
+		t.Fatalf("HandleGetCourseOfferings() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var got courseOfferingsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	var semesters []string
+	for _, o := range got.Offerings {
+		semesters = append(semesters, o.Semester+" "+o.Season)
+	}
+	if want := []string{"2430 Spring", "2530 Spring", "2610 Fall"}; !slices.Equal(semesters, want) {
+		t.Fatalf("offerings = %v, want %v", semesters, want)
+	}
+	if o := got.Offerings[0]; o.Sections != 2 || len(o.Instructors) != 0 {
+		t.Errorf("2430 offering = %+v, want 2 sections and no instructors", o)
+	}
+	if o := got.Offerings[1]; !slices.Equal(o.Instructors, []string{"CHAN, Tai Man"}) {
+		t.Errorf("2530 instructors = %v", o.Instructors)
+	}
+	if o := got.Offerings[2]; o.Title != "Machine Learning" || o.Source != offeringSourceCurrent || !o.LastModified.Equal(modified) {
+		t.Errorf("2610 offering = %+v, want the in-memory course modified at %v", o, modified)
+	}
+	if o := got.Offerings[0]; o.Source != offeringSourceSnapshot || !o.LastModified.Equal(takenAt) {
+		t.Errorf("2430 offering = %+v, want the snapshot taken at %v", o, takenAt)
+	}
+}
+
+func TestHandleGetCourseOfferings_NotFound(t *testing.T) {
+	a := testApp()
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/courses/COMP9999/offerings", a)
+	c.SetParamNames("course")
+	c.SetParamValues("COMP9999")
+	if err := a.HandleGetCourseOfferings(c); err != nil {
+		t.Fatalf("HandleGetCourseOfferings() error: %v", err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	store, err := openSnapshotStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	takenAt := time.Date(2025, 9, 8, 2, 0, 0, 0, time.UTC)
+	course := &Course{Code: "COMP4211", Title: "Machine Learning", CoListWith: []string{"ISDN4211"}}
+	depts := []department{{Code: "COMP", Name: "Computer Science and Engineering", Level: "ug"}}
+	if err := store.save(snapshot{Semester: "2510", TakenAt: takenAt, Departments: depts, Courses: []*Course{course}}); err != nil {
+		t.Fatal(err)
+	}
+	a := testApp()
+	a.endpoint = "http://127.0.0.1:1/2510"
+	a.snapshots = store
+
+	if !a.loadSnapshot() {
+		t.Fatal("loadSnapshot() found no snapshot of 2510")
+	}
+	if !a.ready || !a.lastRefresh.Equal(takenAt) || !a.modified["COMP4211"].Equal(takenAt) {
+		t.Errorf("ready = %v, lastRefresh = %v, modified = %v, want ready as of the snapshot", a.ready, a.lastRefresh, a.modified)
+	}
+	if got, ok := a.lookupCourse(context.Background(), "ISDN4211", "ISDN"); !ok || got.Code != "COMP4211" {
+		t.Errorf("ISDN4211 = %v, want the snapshot's COMP4211", got)
+	}
+	if !slices.Equal(a.departmentCache, depts) {
+		t.Errorf("departments = %v, want the snapshot's %v", a.departmentCache, depts)
+	}
+
+	// Snapshots saved before departments were recorded still serve their
+	// courses, but readiness waits for a crawl to fill in the departments.
+	if err := store.save(snapshot{Semester: "2520", TakenAt: takenAt, Courses: []*Course{course}}); err != nil {
+		t.Fatal(err)
+	}
+	a = testApp()
+	a.endpoint = "http://127.0.0.1:1/2520"
+	a.snapshots = store
+	if !a.loadSnapshot() || a.ready || a.cache["COMP4211"] == nil {
+		t.Errorf("loadSnapshot() of a snapshot without departments: ready = %v, cache = %v, want its courses served but not ready", a.ready, a.cache)
+	}
+
+	a = testApp()
+	a.endpoint = "http://127.0.0.1:1/2530"
+	a.snapshots = store
+	if a.loadSnapshot() || a.ready {
+		t.Error("loadSnapshot() served the snapshot of another semester")
+	}
+}
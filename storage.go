@@ -0,0 +1,503 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// migrations create and evolve the database schema. Each entry is applied
+// once, in order, and recorded in schema_migrations under its index plus
+// one. Applied migrations must never be edited; append new ones instead.
+var migrations = []string{
+	`CREATE TABLE semesters (
+		code       TEXT PRIMARY KEY,
+		name       TEXT NOT NULL,
+		start_date TEXT NOT NULL,
+		end_date   TEXT NOT NULL,
+		crawled_at TEXT NOT NULL
+	);
+	CREATE TABLE departments (
+		semester TEXT NOT NULL REFERENCES semesters (code) ON DELETE CASCADE,
+		code     TEXT NOT NULL,
+		name     TEXT NOT NULL,
+		level    TEXT NOT NULL,
+		PRIMARY KEY (semester, code)
+	);
+	CREATE TABLE courses (
+		semester      TEXT NOT NULL REFERENCES semesters (code) ON DELETE CASCADE,
+		code          TEXT NOT NULL,
+		department    TEXT NOT NULL,
+		title         TEXT NOT NULL,
+		credits       REAL NOT NULL,
+		description   TEXT NOT NULL DEFAULT '',
+		prerequisites TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (semester, code)
+	);
+	CREATE INDEX courses_by_code ON courses (code);
+	CREATE TABLE course_relations (
+		semester TEXT NOT NULL,
+		course   TEXT NOT NULL,
+		kind     TEXT NOT NULL CHECK (kind IN ('co_list', 'exclusion')),
+		position INTEGER NOT NULL,
+		related  TEXT NOT NULL,
+		PRIMARY KEY (semester, course, kind, position),
+		FOREIGN KEY (semester, course) REFERENCES courses (semester, code) ON DELETE CASCADE
+	);
+	CREATE TABLE sections (
+		semester TEXT NOT NULL,
+		course   TEXT NOT NULL,
+		code     TEXT NOT NULL,
+		quota    INTEGER NOT NULL,
+		enrol    INTEGER NOT NULL,
+		avail    INTEGER NOT NULL,
+		wait     INTEGER NOT NULL,
+		PRIMARY KEY (semester, course, code),
+		FOREIGN KEY (semester, course) REFERENCES courses (semester, code) ON DELETE CASCADE
+	);
+	CREATE TABLE meetings (
+		semester   TEXT NOT NULL,
+		course     TEXT NOT NULL,
+		position   INTEGER NOT NULL,
+		section    TEXT NOT NULL,
+		time       TEXT NOT NULL,
+		days       TEXT,
+		start_time TEXT,
+		end_time   TEXT,
+		room       TEXT NOT NULL,
+		PRIMARY KEY (semester, course, position),
+		FOREIGN KEY (semester, course, section) REFERENCES sections (semester, course, code) ON DELETE CASCADE
+	);
+	CREATE TABLE instructors (
+		id   INTEGER PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE
+	);
+	CREATE TABLE section_instructors (
+		semester   TEXT NOT NULL,
+		course     TEXT NOT NULL,
+		section    TEXT NOT NULL,
+		instructor INTEGER NOT NULL REFERENCES instructors (id),
+		PRIMARY KEY (semester, course, section, instructor),
+		FOREIGN KEY (semester, course, section) REFERENCES sections (semester, course, code) ON DELETE CASCADE
+	);`,
+	// Sections of an instructor are read back in the order they were
+	// scraped, so that an unchanged course compares equal after a restart.
+	`ALTER TABLE section_instructors ADD COLUMN position INTEGER NOT NULL DEFAULT 0;`,
+}
+
+// courseStore keeps the crawled catalogue of every semester in a SQLite
+// database, normalized for ad hoc SQL queries.
+type courseStore struct {
+	db *sql.DB
+
+	// terms caches the semesters read by loadTerm until they are saved
+	// again. There are only a few semesters a year, so they are never
+	// evicted.
+	mu    sync.Mutex
+	terms map[string]*archivedTerm
+}
+
+// openStore opens the SQLite database at name, creating it if needed, and
+// brings its schema up to date.
+func openStore(ctx context.Context, name string) (*courseStore, error) {
+	dsn := "file:" + name + "?" + url.Values{"_pragma": {"foreign_keys(1)", "busy_timeout(5000)"}}.Encode()
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	s := &courseStore{db: db, terms: make(map[string]*archivedTerm)}
+	if err := s.migrate(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrating database %s: %w", name, err)
+	}
+	return s, nil
+}
+
+func (s *courseStore) Close() error {
+	return s.db.Close()
+}
+
+// migrate applies the migrations the database has not seen yet.
+func (s *courseStore) migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TEXT NOT NULL
+	)`); err != nil {
+		return err
+	}
+	var version int
+	if err := s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("schema version %d is newer than this build supports (%d)", version, len(migrations))
+	}
+	for i := version; i < len(migrations); i++ {
+		err := s.inTx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)`,
+				i+1, time.Now().UTC().Format(time.RFC3339))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (s *courseStore) inTx(ctx context.Context, f func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := f(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// meetingTime matches section times such as "TuTh 03:00PM - 04:20PM".
+var meetingTime = regexp.MustCompile(`^([A-Za-z]+) ([0-9]{2}:[0-9]{2}[AP]M) - ([0-9]{2}:[0-9]{2}[AP]M)$`)
+
+// parseMeetingTime splits a section time into its days and 24-hour start and
+// end times. Times that do not follow the usual format, such as TBA, give
+// NULLs.
+func parseMeetingTime(s string) (days, start, end sql.NullString) {
+	m := meetingTime.FindStringSubmatch(s)
+	if m == nil {
+		return
+	}
+	from, err := time.Parse("03:04PM", m[2])
+	if err != nil {
+		return
+	}
+	to, err := time.Parse("03:04PM", m[3])
+	if err != nil {
+		return
+	}
+	return sql.NullString{String: m[1], Valid: true},
+		sql.NullString{String: from.Format("15:04"), Valid: true},
+		sql.NullString{String: to.Format("15:04"), Valid: true}
+}
+
+// saveTerm stores the crawled departments of a semester, replacing the
+// courses stored for those departments only. Departments missing from the
+// crawl keep whatever was stored for them before.
+func (s *courseStore) saveTerm(ctx context.Context, sem semester, crawledAt time.Time, departments []department, courses []*Course) error {
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO semesters (code, name, start_date, end_date, crawled_at) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT (code) DO UPDATE SET name = excluded.name, start_date = excluded.start_date,
+				end_date = excluded.end_date, crawled_at = excluded.crawled_at`,
+			sem.Code, sem.Name, sem.Start, sem.End, crawledAt.UTC().Format(time.RFC3339Nano)); err != nil {
+			return err
+		}
+		for _, d := range departments {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO departments (semester, code, name, level) VALUES (?, ?, ?, ?)
+				ON CONFLICT (semester, code) DO UPDATE SET name = excluded.name, level = excluded.level`,
+				sem.Code, d.Code, d.Name, d.Level); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, `DELETE FROM courses WHERE semester = ? AND department = ?`, sem.Code, d.Code); err != nil {
+				return err
+			}
+		}
+		for _, course := range courses {
+			// A course may be listed under a department other than its own.
+			if _, err := tx.ExecContext(ctx, `DELETE FROM courses WHERE semester = ? AND code = ?`, sem.Code, course.Code); err != nil {
+				return err
+			}
+			if err := insertCourse(ctx, tx, sem.Code, course); err != nil {
+				return fmt.Errorf("storing %s: %w", course.Code, err)
+			}
+		}
+		return nil
+	})
+	s.mu.Lock()
+	delete(s.terms, sem.Code)
+	s.mu.Unlock()
+	return err
+}
+
+func insertCourse(ctx context.Context, tx *sql.Tx, semester string, course *Course) error {
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO courses (semester, code, department, title, credits, description, prerequisites) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		semester, course.Code, extractDepartment(course.Code), course.Title, course.Credits, course.Description, course.Prerequisites); err != nil {
+		return err
+	}
+	for kind, related := range map[string][]string{"co_list": course.CoListWith, "exclusion": course.Exclusions} {
+		for i, code := range related {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO course_relations (semester, course, kind, position, related) VALUES (?, ?, ?, ?, ?)`,
+				semester, course.Code, kind, i, code); err != nil {
+				return err
+			}
+		}
+	}
+	var sections []string
+	for i, section := range course.Schedule {
+		// A section meeting at several times appears once per meeting; its
+		// enrolment figures are the same in each.
+		if !slices.Contains(sections, section.Code) {
+			sections = append(sections, section.Code)
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO sections (semester, course, code, quota, enrol, avail, wait) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+				semester, course.Code, section.Code, section.Quota, section.Enrol, section.Avail, section.Wait); err != nil {
+				return err
+			}
+		}
+		days, start, end := parseMeetingTime(section.Time)
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO meetings (semester, course, position, section, time, days, start_time, end_time, room) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			semester, course.Code, i, section.Code, section.Time, days, start, end, section.Room); err != nil {
+			return err
+		}
+	}
+	for name, taught := range course.Instructors {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO instructors (name) VALUES (?)`, name); err != nil {
+			return err
+		}
+		for i, section := range taught {
+			if !slices.Contains(sections, section) {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx,
+				`INSERT OR IGNORE INTO section_instructors (semester, course, section, instructor, position)
+				SELECT ?, ?, ?, id, ? FROM instructors WHERE name = ?`,
+				semester, course.Code, section, i, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadTerm reads back a semester saved by saveTerm, or reports
+// ErrSemesterNotAvailable if it was never stored.
+func (s *courseStore) loadTerm(ctx context.Context, code string) (*archivedTerm, error) {
+	s.mu.Lock()
+	t, ok := s.terms[code]
+	s.mu.Unlock()
+	if ok {
+		return t, nil
+	}
+	t, err := s.readTerm(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.terms[code] = t
+	s.mu.Unlock()
+	return t, nil
+}
+
+// readTerm reads a semester from the database.
+func (s *courseStore) readTerm(ctx context.Context, code string) (*archivedTerm, error) {
+	var crawledAt string
+	err := s.db.QueryRowContext(ctx, `SELECT crawled_at FROM semesters WHERE code = ?`, code).Scan(&crawledAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrSemesterNotAvailable, code)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t := &archivedTerm{semester: code, departments: []department{}}
+	if t.lastModified, err = time.Parse(time.RFC3339Nano, crawledAt); err != nil {
+		return nil, fmt.Errorf("semester %s crawled_at: %w", code, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT code, name, level FROM departments WHERE semester = ? ORDER BY code`, code)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d department
+		if err := rows.Scan(&d.Code, &d.Name, &d.Level); err != nil {
+			return nil, err
+		}
+		t.departments = append(t.departments, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if t.courses, err = s.courses(ctx, `c.semester = ?`, code); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// courseHistory returns the stored course with the given code, by semester.
+func (s *courseStore) courseHistory(ctx context.Context, code string) (map[string]recordedCourse, error) {
+	bySemester := make(map[string]recordedCourse)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT c.semester, s.crawled_at FROM courses c JOIN semesters s ON s.code = c.semester WHERE c.code = ?`, code)
+	if err != nil {
+		return nil, err
+	}
+	crawledAt := make(map[string]time.Time)
+	for rows.Next() {
+		var semester, at string
+		if err := rows.Scan(&semester, &at); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if crawledAt[semester], err = time.Parse(time.RFC3339Nano, at); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("semester %s crawled_at: %w", semester, err)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for semester, at := range crawledAt {
+		courses, err := s.courses(ctx, `c.semester = ? AND c.code = ?`, semester, code)
+		if err != nil {
+			return nil, err
+		}
+		bySemester[semester] = recordedCourse{courses[code], offeringSourceDatabase, at}
+	}
+	return bySemester, nil
+}
+
+// courses reads the courses matching where, a condition on the courses table
+// aliased c that must select a single semester, by code.
+func (s *courseStore) courses(ctx context.Context, where string, args ...any) (map[string]*Course, error) {
+	courses := make(map[string]*Course)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT c.code, c.title, c.credits, c.description, c.prerequisites FROM courses c WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		course := &Course{Instructors: make(map[string][]string)}
+		if err := rows.Scan(&course.Code, &course.Title, &course.Credits, &course.Description, &course.Prerequisites); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		courses[course.Code] = course
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	relations, err := s.db.QueryContext(ctx,
+		`SELECT r.course, r.kind, r.related FROM course_relations r
+		JOIN courses c ON c.semester = r.semester AND c.code = r.course
+		WHERE `+where+` ORDER BY r.course, r.kind, r.position`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer relations.Close()
+	for relations.Next() {
+		var code, kind, related string
+		if err := relations.Scan(&code, &kind, &related); err != nil {
+			return nil, err
+		}
+		course := courses[code]
+		if kind == "co_list" {
+			course.CoListWith = append(course.CoListWith, related)
+		} else {
+			course.Exclusions = append(course.Exclusions, related)
+		}
+	}
+	if err := relations.Err(); err != nil {
+		return nil, err
+	}
+
+	meetings, err := s.db.QueryContext(ctx,
+		`SELECT m.course, m.section, m.time, m.room, s.quota, s.enrol, s.avail, s.wait FROM meetings m
+		JOIN sections s ON s.semester = m.semester AND s.course = m.course AND s.code = m.section
+		JOIN courses c ON c.semester = m.semester AND c.code = m.course
+		WHERE `+where+` ORDER BY m.course, m.position`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer meetings.Close()
+	for meetings.Next() {
+		var code string
+		var section Section
+		if err := meetings.Scan(&code, &section.Code, &section.Time, &section.Room, &section.Quota, &section.Enrol, &section.Avail, &section.Wait); err != nil {
+			return nil, err
+		}
+		course := courses[code]
+		course.Sections = append(course.Sections, section.Code)
+		course.Schedule = append(course.Schedule, section)
+	}
+	if err := meetings.Err(); err != nil {
+		return nil, err
+	}
+
+	instructors, err := s.db.QueryContext(ctx,
+		`SELECT si.course, i.name, si.section FROM section_instructors si
+		JOIN instructors i ON i.id = si.instructor
+		JOIN courses c ON c.semester = si.semester AND c.code = si.course
+		WHERE `+where+` ORDER BY si.course, i.name, si.position, si.section`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer instructors.Close()
+	for instructors.Next() {
+		var code, name, section string
+		if err := instructors.Scan(&code, &name, &section); err != nil {
+			return nil, err
+		}
+		course := courses[code]
+		course.Instructors[name] = append(course.Instructors[name], section)
+	}
+	return courses, instructors.Err()
+}
+
+// loadStoredSemester serves the current semester from the database, if it
+// was stored, until a crawl replaces it, and reports whether it was.
+func (a *app) loadStoredSemester(ctx context.Context) (bool, error) {
+	if a.store == nil {
+		return false, nil
+	}
+	t, err := a.store.loadTerm(ctx, a.semester())
+	if errors.Is(err, ErrSemesterNotAvailable) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	a.replaceCourses(&crawl{
+		endpoint:    a.getEndpoint(),
+		departments: t.departments,
+		courses:     t.courses,
+		crawledAt:   t.lastModified,
+	})
+	a.markReady(t.lastModified)
+	return true, nil
+}
+
+// storeSemester saves a completed crawl of semester to the database, if
+// configured.
+func (a *app) storeSemester(ctx context.Context, semester string, c *crawl) error {
+	if a.store == nil {
+		return nil
+	}
+	s, err := a.calendar.parseSemester(semester)
+	if err != nil {
+		return err
+	}
+	courses := slices.Collect(maps.Values(c.courses))
+	return a.store.saveTerm(ctx, s, c.crawledAt, c.departments, courses)
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testStore(t *testing.T) (*courseStore, string) {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "courses.db")
+	store, err := openStore(t.Context(), name)
+	if err != nil {
+		t.Fatalf("openStore() error: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store, name
+}
+
+func storedCourse() *Course {
+	return &Course{
+		Code:          "COMP1021",
+		Title:         "Introduction to Computer Science",
+		Credits:       3,
+		Description:   "Fundamental concepts of computing.",
+		Prerequisites: "None",
+		CoListWith:    []string{"ISDN1021"},
+		Exclusions:    []string{"COMP1022P", "COMP1029P"},
+		Sections:      []string{"L1", "LA1"},
+		Schedule: []Section{
+			{Code: "L1", Time: "TuTh 03:00PM - 04:20PM", Room: "LTA", Quota: 200, Enrol: 180, Avail: 20},
+			{Code: "LA1", Time: "TBA", Room: "Lab 4210", Quota: 40, Enrol: 40, Wait: 3},
+		},
+		Instructors: map[string][]string{"CHAN, Tai Man": {"L1"}, "LEE, Siu Ming": {"L1", "LA1"}},
+	}
+}
+
+func TestOpenStore_Migrations(t *testing.T) {
+	store, name := testStore(t)
+	var version int
+	if err := store.db.QueryRow(`SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		t.Fatal(err)
+	}
+	if version != len(migrations) {
+		t.Errorf("schema version = %d, want %d", version, len(migrations))
+	}
+	store.Close()
+
+	// Reopening must not apply the migrations again.
+	reopened, err := openStore(t.Context(), name)
+	if err != nil {
+		t.Fatalf("openStore() reopen error: %v", err)
+	}
+	defer reopened.Close()
+	var applied int
+	if err := reopened.db.QueryRow(`SELECT COUNT(*) FROM schema_migrations`).Scan(&applied); err != nil {
+		t.Fatal(err)
+	}
+	if applied != len(migrations) {
+		t.Errorf("migrations recorded = %d, want %d", applied, len(migrations))
+	}
+}
+
+func TestCourseStore_SaveAndLoad(t *testing.T) {
+	store, _ := testStore(t)
+	s, err := defaultCalendar().parseSemester("2510")
+	if err != nil {
+		t.Fatal(err)
+	}
+	crawledAt := time.Date(2025, 9, 1, 2, 0, 0, 0, time.UTC)
+	departments := []department{{Code: "COMP", Name: "Computer Science and Engineering", Level: "ug"}}
+	want := storedCourse()
+	// Saving twice replaces the semester rather than duplicating it.
+	for range 2 {
+		if err := store.saveTerm(t.Context(), s, crawledAt, departments, []*Course{want}); err != nil {
+			t.Fatalf("saveTerm() error: %v", err)
+		}
+	}
+
+	term, err := store.loadTerm(t.Context(), "2510")
+	if err != nil {
+		t.Fatalf("loadTerm() error: %v", err)
+	}
+	if !term.lastModified.Equal(crawledAt) || !reflect.DeepEqual(term.departments, departments) {
+		t.Errorf("term = %+v", term)
+	}
+	if got := term.courses["COMP1021"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded course = %+v, want %+v", got, want)
+	}
+
+	var days, start, end string
+	err = store.db.QueryRow(`SELECT days, start_time, end_time FROM meetings WHERE course = 'COMP1021' AND section = 'L1'`).Scan(&days, &start, &end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if days != "TuTh" || start != "15:00" || end != "16:20" {
+		t.Errorf("L1 meets %s %s-%s, want TuTh 15:00-16:20", days, start, end)
+	}
+	var instructors int
+	if err := store.db.QueryRow(`SELECT COUNT(*) FROM instructors`).Scan(&instructors); err != nil {
+		t.Fatal(err)
+	}
+	if instructors != 2 {
+		t.Errorf("instructors = %d, want 2", instructors)
+	}
+
+	if _, err := store.loadTerm(t.Context(), "2430"); err == nil {
+		t.Error("loadTerm(2430) succeeded, want ErrSemesterNotAvailable")
+	}
+}
+
+func TestCourseStore_InstructorSectionOrder(t *testing.T) {
+	store, _ := testStore(t)
+	s, err := defaultCalendar().parseSemester("2510")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Course{
+		Code:        "COMP2011",
+		Sections:    []string{"L2", "L10"},
+		Schedule:    []Section{{Code: "L2", Time: "TBA"}, {Code: "L10", Time: "TBA"}},
+		Instructors: map[string][]string{"LAM, Gibson": {"L2", "L10"}},
+	}
+	if err := store.saveTerm(t.Context(), s, time.Now(), nil, []*Course{want}); err != nil {
+		t.Fatalf("saveTerm() error: %v", err)
+	}
+	term, err := store.loadTerm(t.Context(), "2510")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := term.courses["COMP2011"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded course = %+v, want %+v", got, want)
+	}
+}
+
+func TestHandleGetCourse_StoredSemester(t *testing.T) {
+	store, _ := testStore(t)
+	s, err := defaultCalendar().parseSemester("2430")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.saveTerm(t.Context(), s, time.Now(), nil, []*Course{storedCourse()}); err != nil {
+		t.Fatal(err)
+	}
+	a := testApp()
+	a.store = store
+	a.endpoint = "http://127.0.0.1:1/2510"
+
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/courses/COMP1021?semester=2430", a)
+	c.SetParamNames("course")
+	c.SetParamValues("COMP1021")
+	if err := a.HandleGetCourse(c); err != nil {
+		t.Fatalf("HandleGetCourse() error: %v", err)
+	}
+	var got Course
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal error: %v", err)
+	}
+	if rec.Code != http.StatusOK || got.Title != "Introduction to Computer Science" {
+		t.Errorf("status = %d, course = %+v", rec.Code, got)
+	}
+
+	c, rec = setupHandlerTest(http.MethodGet, "/v1/courses/COMP1021?semester=2440", a)
+	c.SetParamNames("course")
+	c.SetParamValues("COMP1021")
+	if err := a.HandleGetCourse(c); err != nil {
+		t.Fatalf("HandleGetCourse() error: %v", err)
+	}
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unstored semester status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+
+	offerings, err := a.courseOfferings(t.Context(), "COMP1021")
+	if err != nil || len(offerings) != 1 || offerings[0].Semester != "2430" || offerings[0].Instructors[0] != "CHAN, Tai Man" || offerings[0].Source != offeringSourceDatabase {
+		t.Errorf("courseOfferings() = %+v, %v, want the stored 2430 offering", offerings, err)
+	}
+}
+
+func TestCourseStore_SaveKeepsOtherDepartments(t *testing.T) {
+	store, _ := testStore(t)
+	s, err := defaultCalendar().parseSemester("2510")
+	if err != nil {
+		t.Fatal(err)
+	}
+	comp := []department{{Code: "COMP"}}
+	math := []department{{Code: "MATH"}}
+	saves := []struct {
+		departments []department
+		courses     []*Course
+	}{
+		{append(comp, math...), []*Course{storedCourse(), {Code: "COMP2011"}, {Code: "MATH1013"}}},
+		// COMP2011 is no longer offered; MATH was not crawled.
+		{comp, []*Course{storedCourse()}},
+	}
+	for _, save := range saves {
+		if err := store.saveTerm(t.Context(), s, time.Now(), save.departments, save.courses); err != nil {
+			t.Fatalf("saveTerm() error: %v", err)
+		}
+	}
+	term, err := store.loadTerm(t.Context(), "2510")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(term.courses) != 2 || term.courses["COMP1021"] == nil || term.courses["MATH1013"] == nil {
+		t.Errorf("stored courses = %v, want COMP1021 and MATH1013", term.courses)
+	}
+}
+
+func TestPreCacheCurrentSemesterCourses_StoresCompleteCrawls(t *testing.T) {
+	store, _ := testStore(t)
+	a := testApp()
+	a.store = store
+	a.endpoint = upstreamServer(t, "COMP", "MATH").URL + "/2510"
+	if err := a.PreCacheCurrentSemesterCourses(t.Context()); err == nil {
+		t.Fatal("PreCacheCurrentSemesterCourses() succeeded without the MATH page")
+	}
+	if _, err := store.loadTerm(t.Context(), "2510"); !errors.Is(err, ErrSemesterNotAvailable) {
+		t.Errorf("loadTerm() after an incomplete crawl error = %v, want ErrSemesterNotAvailable", err)
+	}
+
+	a.endpoint = upstreamServer(t, "COMP").URL + "/2510"
+	if err := a.PreCacheCurrentSemesterCourses(t.Context()); err != nil {
+		t.Fatalf("PreCacheCurrentSemesterCourses() error: %v", err)
+	}
+	term, err := store.loadTerm(t.Context(), "2510")
+	if err != nil || term.courses["COMP1021"] == nil {
+		t.Errorf("loadTerm() = %v, %v, want the crawled COMP1021", term, err)
+	}
+}
+
+func TestPrecacheSemester_StoresCrawledSemester(t *testing.T) {
+	store, _ := testStore(t)
+	a := testApp()
+	a.store = store
+	a.config.BaseURL = upstreamServer(t, "COMP").URL
+	a.endpoint = a.config.BaseURL + "/2440"
+	if err := a.precacheSemester(t.Context(), a.config.BaseURL+"/2510", true); err != nil {
+		t.Fatalf("precacheSemester() error: %v", err)
+	}
+	if term, err := store.loadTerm(t.Context(), "2510"); err != nil || term.courses["COMP1021"] == nil {
+		t.Errorf("loadTerm(2510) = %v, %v, want the crawled COMP1021", term, err)
+	}
+	if _, err := store.loadTerm(t.Context(), "2440"); !errors.Is(err, ErrSemesterNotAvailable) {
+		t.Errorf("loadTerm(2440) error = %v, want ErrSemesterNotAvailable", err)
+	}
+}
+
+func TestCourseStore_LoadTermCached(t *testing.T) {
+	store, _ := testStore(t)
+	s, err := defaultCalendar().parseSemester("2430")
+	if err != nil {
+		t.Fatal(err)
+	}
+	save := func() {
+		t.Helper()
+		if err := store.saveTerm(t.Context(), s, time.Now(), nil, []*Course{storedCourse()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	save()
+	first, err := store.loadTerm(t.Context(), "2430")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := store.loadTerm(t.Context(), "2430"); again != first {
+		t.Error("loadTerm() read the semester again instead of using the cached one")
+	}
+	save()
+	if again, _ := store.loadTerm(t.Context(), "2430"); again == first {
+		t.Error("loadTerm() served the cached semester after it was saved again")
+	}
+}
+
+func TestLoadStoredSemester(t *testing.T) {
+	store, _ := testStore(t)
+	s, err := defaultCalendar().parseSemester("2510")
+	if err != nil {
+		t.Fatal(err)
+	}
+	crawledAt := time.Date(2025, 9, 1, 2, 0, 0, 0, time.UTC)
+	departments := []department{{Code: "COMP", Name: "Computer Science and Engineering", Level: "ug"}}
+	if err := store.saveTerm(t.Context(), s, crawledAt, departments, []*Course{storedCourse()}); err != nil {
+		t.Fatal(err)
+	}
+	a := testApp()
+	a.store = store
+	a.endpoint = "http://127.0.0.1:1/2510"
+
+	loaded, err := a.loadStoredSemester(t.Context())
+	if err != nil || !loaded {
+		t.Fatalf("loadStoredSemester() = %v, %v, want loaded", loaded, err)
+	}
+	if !a.ready || !a.lastRefresh.Equal(crawledAt) || len(a.departmentCache) != 1 {
+		t.Errorf("ready = %v, lastRefresh = %v, departments = %v, want the stored semester", a.ready, a.lastRefresh, a.departmentCache)
+	}
+	if course, ok := a.lookupCourse(t.Context(), "COMP1021", "COMP"); !ok || course.Title != "Introduction to Computer Science" {
+		t.Errorf("COMP1021 = %v, want the stored course", course)
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultSuggestLimit = 10
+	maxSuggestLimit     = 50
+)
+
+// prefixNode is a node of a byte-wise trie mapping keys to the courses they
+// belong to.
+type prefixNode struct {
+	children map[byte]*prefixNode
+	// codes holds the courses of the key ending at this node.
+	codes map[string]struct{}
+	// below lists, sorted, the courses of every key ending at or below this
+	// node, so that a prefix lookup can stop after the first few; keys counts
+	// those keys per course.
+	below []string
+	keys  map[string]int
+}
+
+func (n *prefixNode) insert(key, code string) {
+	var path []*prefixNode
+	for i := 0; i < len(key); i++ {
+		if n.children == nil {
+			n.children = make(map[byte]*prefixNode)
+		}
+		child, ok := n.children[key[i]]
+		if !ok {
+			child = &prefixNode{}
+			n.children[key[i]] = child
+		}
+		n = child
+		path = append(path, n)
+	}
+	if _, ok := n.codes[code]; ok {
+		return
+	}
+	if n.codes == nil {
+		n.codes = make(map[string]struct{})
+	}
+	n.codes[code] = struct{}{}
+	for _, p := range path {
+		p.count(code, 1)
+	}
+}
+
+// remove drops code from key. Emptied nodes are kept; they are discarded
+// with the whole trie when the cache is reset.
+func (n *prefixNode) remove(key, code string) {
+	var path []*prefixNode
+	for i := 0; i < len(key) && n != nil; i++ {
+		n = n.children[key[i]]
+		path = append(path, n)
+	}
+	if n == nil {
+		return
+	}
+	if _, ok := n.codes[code]; !ok {
+		return
+	}
+	delete(n.codes, code)
+	for _, p := range path {
+		p.count(code, -1)
+	}
+}
+
+// count adds delta to the keys of code at or below n, listing code in
+// n.below while it has any.
+func (n *prefixNode) count(code string, delta int) {
+	if n.keys == nil {
+		n.keys = make(map[string]int)
+	}
+	n.keys[code] += delta
+	i, listed := slices.BinarySearch(n.below, code)
+	switch {
+	case n.keys[code] == 0:
+		delete(n.keys, code)
+		if listed {
+			n.below = slices.Delete(n.below, i, i+1)
+		}
+	case !listed:
+		n.below = slices.Insert(n.below, i, code)
+	}
+}
+
+func (n *prefixNode) find(prefix string) *prefixNode {
+	for i := 0; i < len(prefix) && n != nil; i++ {
+		n = n.children[prefix[i]]
+	}
+	return n
+}
+
+// suggestIndex finds cached courses of the current term by a prefix of their
+// code or of a word in their title. It is guarded by app.mu.
+type suggestIndex struct {
+	codes prefixNode
+	words prefixNode
+	// titleWords holds the indexed title words of every course.
+	titleWords map[string][]string
+}
+
+func newSuggestIndex() *suggestIndex {
+	return &suggestIndex{titleWords: make(map[string][]string)}
+}
+
+// codeKey turns a course code or a prefix of one into the form used as a key,
+// so that "comp 10" finds COMP1021.
+func codeKey(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(courseCodeSeparators.Replace(s)), ""))
+}
+
+// add indexes course, replacing any earlier version of it.
+func (idx *suggestIndex) add(course *Course) {
+	idx.codes.insert(codeKey(course.Code), course.Code)
+	for _, word := range idx.titleWords[course.Code] {
+		idx.words.remove(word, course.Code)
+	}
+	words := searchTokens(course.Title)
+	for _, word := range words {
+		idx.words.insert(word, course.Code)
+	}
+	idx.titleWords[course.Code] = words
+}
+
+// suggest returns up to limit codes accepted by keep: those of courses whose
+// code starts with prefix, followed by those with title words starting with
+// the words of prefix. Both groups are sorted by code.
+func (idx *suggestIndex) suggest(prefix string, limit int, keep func(code string) bool) []string {
+	var codes []string
+	if key := codeKey(prefix); key != "" {
+		if n := idx.codes.find(key); n != nil {
+			for _, code := range n.below {
+				if !keep(code) {
+					continue
+				}
+				if codes = append(codes, code); len(codes) == limit {
+					return codes
+				}
+			}
+		}
+	}
+
+	words := searchTokens(prefix)
+	if len(words) == 0 {
+		return codes
+	}
+	// Only the last word is still being typed; earlier ones are complete.
+	last, complete := words[len(words)-1], words[:len(words)-1]
+	n := idx.words.find(last)
+	if n == nil {
+		return codes
+	}
+	byCode := len(codes)
+	for _, code := range n.below {
+		if slices.Contains(codes[:byCode], code) || !containsAll(idx.titleWords[code], complete) || !keep(code) {
+			continue
+		}
+		if codes = append(codes, code); len(codes) == limit {
+			break
+		}
+	}
+	return codes
+}
+
+func containsAll(words, required []string) bool {
+	for _, word := range required {
+		if !slices.Contains(words, word) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *app) HandleSuggestCourses(c echo.Context) error {
+	a.logger.Info("GET /v1/suggest")
+	prefix := strings.TrimLeft(c.QueryParam("prefix"), " ")
+	if strings.TrimSpace(prefix) == "" {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: ErrEmptyPrefix.Error(),
+		})
+		return nil
+	}
+	limit, err := parseLimit(c, defaultSuggestLimit, maxSuggestLimit)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse{
+			Status:  "error",
+			Message: err.Error(),
+		})
+		return nil
+	}
+	c.JSON(http.StatusOK, a.suggestCourses(prefix, limit))
+	return nil
+}
+
+// suggestCourses returns up to limit cached courses of the current term
+// matching prefix.
+func (a *app) suggestCourses(prefix string, limit int) suggestResponse {
+	resp := suggestResponse{Prefix: prefix, Suggestions: []suggestion{}}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if a.suggestions == nil {
+		return resp
+	}
+	cached := func(code string) bool {
+		_, ok := a.cache[code]
+		return ok
+	}
+	for _, code := range a.suggestions.suggest(prefix, limit, cached) {
+		course := a.cache[code]
+		resp.Suggestions = append(resp.Suggestions, suggestion{
+			Code:    course.Code,
+			Title:   course.Title,
+			Credits: course.Credits,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+)
+
+func suggestCodes(a *app, prefix string, limit int) []string {
+	var codes []string
+	for _, s := range a.suggestCourses(prefix, limit).Suggestions {
+		codes = append(codes, s.Code)
+	}
+	return codes
+}
+
+func TestSuggestCourses(t *testing.T) {
+	a := testApp()
+	for _, course := range []*Course{
+		{Code: "COMP1021", Title: "Introduction to Computer Science", Credits: 3},
+		{Code: "COMP1022P", Title: "Introduction to Python Programming", Credits: 3},
+		{Code: "COMP2011", Title: "Programming with C++", Credits: 4},
+		{Code: "COMP4211", Title: "Machine Learning", Credits: 3},
+		{Code: "MATH1013", Title: "Calculus IB", Credits: 3},
+	} {
+		a.remember(&CourseParsingResult{Code: course.Code, Course: course})
+	}
+
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"comp10", 10, []string{"COMP1021", "COMP1022P"}},
+		{"COMP 102", 10, []string{"COMP1021", "COMP1022P"}},
+		{"comp", 2, []string{"COMP1021", "COMP1022P"}},
+		{"progr", 10, []string{"COMP1022P", "COMP2011"}},
+		{"python prog", 10, []string{"COMP1022P"}},
+		{"machine le", 10, []string{"COMP4211"}},
+		{"physics", 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			if got := suggestCodes(a, tt.prefix, tt.limit); !slices.Equal(got, tt.want) {
+				t.Errorf("suggest(%q) = %v, want %v", tt.prefix, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestCourses_RenamedCourse(t *testing.T) {
+	a := testApp()
+	a.remember(&CourseParsingResult{Code: "COMP4211", Course: &Course{Code: "COMP4211", Title: "Machine Learning"}})
+	a.remember(&CourseParsingResult{Code: "COMP4211", Course: &Course{Code: "COMP4211", Title: "Deep Learning"}})
+
+	if got := suggestCodes(a, "mach", 10); got != nil {
+		t.Errorf("suggest(mach) = %v, want none", got)
+	}
+	if got := suggestCodes(a, "deep", 10); !slices.Equal(got, []string{"COMP4211"}) {
+		t.Errorf("suggest(deep) = %v, want [COMP4211]", got)
+	}
+}
+
+func TestSuggestCourses_SharedWordPrefix(t *testing.T) {
+	a := testApp()
+	a.remember(&CourseParsingResult{Code: "COMP4332", Course: &Course{Code: "COMP4332", Title: "Databases and Data Mining"}})
+	a.remember(&CourseParsingResult{Code: "COMP4332", Course: &Course{Code: "COMP4332", Title: "Data Mining"}})
+
+	if got := suggestCodes(a, "datab", 10); got != nil {
+		t.Errorf("suggest(datab) = %v, want none", got)
+	}
+	if got := suggestCodes(a, "dat", 10); !slices.Equal(got, []string{"COMP4332"}) {
+		t.Errorf("suggest(dat) = %v, want [COMP4332]", got)
+	}
+}
+
+func TestHandleSuggestCourses(t *testing.T) {
+	a := testApp()
+	a.remember(&CourseParsingResult{Code: "COMP1021", Course: &Course{Code: "COMP1021", Title: "Introduction to Computer Science", Credits: 3}})
+
+	c, rec := setupHandlerTest(http.MethodGet, "/v1/suggest?prefix=comp10", a)
+	if err := a.HandleSuggestCourses(c); err != nil {
+		t.Fatalf("HandleSuggestCourses() error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var resp suggestResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	want := []suggestion{{Code: "COMP1021", Title: "Introduction to Computer Science", Credits: 3}}
+	if !slices.Equal(resp.Suggestions, want) {
+		t.Errorf("suggestions = %+v, want %+v", resp.Suggestions, want)
+	}
+
+	c, rec = setupHandlerTest(http.MethodGet, "/v1/suggest?prefix=+", a)
+	if err := a.HandleSuggestCourses(c); err != nil {
+		t.Fatalf("HandleSuggestCourses() error: %v", err)
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("empty prefix status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// withSpanRecorder installs an in-memory tracer provider for the duration of
+// the test and returns the exporter collecting finished spans.
+func withSpanRecorder(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		provider.Shutdown(t.Context())
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return exporter
+}
+
+func spanNames(spans tracetest.SpanStubs) map[string]tracetest.SpanStub {
+	names := make(map[string]tracetest.SpanStub)
+	for _, s := range spans {
+		names[s.Name] = s
+	}
+	return names
+}
+
+func TestRequestTracing_PropagatesTraceContext(t *testing.T) {
+	exporter := withSpanRecorder(t)
+	e := echo.New()
+	e.Use(requestTracing)
+	e.GET("/healthz", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.Header.Set("traceparent", fmt.Sprintf("00-%s-00f067aa0ba902b7-01", traceID))
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("len(spans) = %d, want 1", len(spans))
+	}
+	if got := spans[0].SpanContext.TraceID().String(); got != traceID {
+		t.Errorf("trace ID = %s, want %s", got, traceID)
+	}
+	if spans[0].Name != "GET /healthz" {
+		t.Errorf("span name = %q, want %q", spans[0].Name, "GET /healthz")
+	}
+}
+
+func TestHandleGetCourse_CacheMissSpans(t *testing.T) {
+	exporter := withSpanRecorder(t)
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><div class="course"><div class="courseinfo"><div class="courseattrContainer">
+			<div class="subject">COMP 1021 - Introduction to Computer Science (3 units)</div>
+		</div></div></div></body></html>`)
+	}))
+	defer srv.Close()
+
+	a := testApp()
+	a.endpoint = srv.URL
+	a.server = echo.New()
+	a.server.Use(requestTracing)
+	a.server.GET("/v1/courses/:course", a.HandleGetCourse)
+
+	rec := httptest.NewRecorder()
+	a.server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/courses/COMP1021", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if traceparent == "" {
+		t.Error("upstream request carried no traceparent header")
+	}
+
+	spans := spanNames(exporter.GetSpans())
+	server, ok := spans["GET /v1/courses/:course"]
+	if !ok {
+		t.Fatalf("missing server span, got %v", spans)
+	}
+	for _, name := range []string{"cache.lookup", "GetCourse", "GET /subject/COMP", "ParseCourse"} {
+		s, ok := spans[name]
+		if !ok {
+			t.Errorf("missing span %q", name)
+			continue
+		}
+		if s.SpanContext.TraceID() != server.SpanContext.TraceID() {
+			t.Errorf("span %q not in request trace", name)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"reflect"
+
+	"github.com/hkust-cse/crapi/courseinfopb"
+)
+
+// watcherBuffer is the number of course changes buffered per watcher before
+// further changes are dropped for it.
+const watcherBuffer = 64
+
+type courseChange struct {
+	Type   courseinfopb.CourseChange_Type
+	Course *Course
+}
+
+// watch registers a watcher for cache changes. The returned function
+// unregisters it and must be called once the watcher is done.
+func (a *app) watch() (<-chan courseChange, func()) {
+	ch := make(chan courseChange, watcherBuffer)
+	a.watchersMu.Lock()
+	if a.watchers == nil {
+		a.watchers = make(map[chan courseChange]struct{})
+	}
+	a.watchers[ch] = struct{}{}
+	a.watchersMu.Unlock()
+	return ch, func() {
+		a.watchersMu.Lock()
+		delete(a.watchers, ch)
+		a.watchersMu.Unlock()
+	}
+}
+
+// notify sends a change to every watcher without blocking the scraper;
+// watchers that have fallen behind miss the change. A nil course reports the
+// removal of previous.
+func (a *app) notify(previous, course *Course) {
+	change := courseChange{Type: courseinfopb.CourseChange_TYPE_ADDED, Course: course}
+	switch {
+	case course == nil:
+		change = courseChange{Type: courseinfopb.CourseChange_TYPE_REMOVED, Course: previous}
+	case previous != nil:
+		if reflect.DeepEqual(previous, course) {
+			return
+		}
+		change.Type = courseinfopb.CourseChange_TYPE_UPDATED
+	}
+	a.watchersMu.Lock()
+	defer a.watchersMu.Unlock()
+	for ch := range a.watchers {
+		select {
+		case ch <- change:
+		default:
+			a.logger.Warn("Dropping course change for slow watcher", "courseCode", change.Course.Code)
+		}
+	}
+}